@@ -0,0 +1,325 @@
+package dxl
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Control Table Backup instruction parameters (Protocol 2.0)
+var (
+	backupStoreParams   = []byte{0x01, 0x43, 0x54, 0x52, 0x4C} // 0x01 + "CTRL"
+	backupRestoreParams = []byte{0x02, 0x43, 0x54, 0x52, 0x4C} // 0x02 + "CTRL"
+)
+
+// BackupControlTable stores the motor's current control table (EEPROM and RAM
+// settings) in its on-board backup area. Torque must be disabled.
+// Backup Ready (X-series: 147) reads 1 once a backup exists.
+func (d *Driver) BackupControlTable(id uint8) error {
+	if err := d.checkSupported(InstBackup, "control table backup"); err != nil {
+		return err
+	}
+	return d.sendInstruction(id, InstBackup, backupStoreParams)
+}
+
+// RestoreControlTable loads the control table from the on-board backup area.
+// Torque must be disabled.
+func (d *Driver) RestoreControlTable(id uint8) error {
+	if err := d.checkSupported(InstBackup, "control table backup"); err != nil {
+		return err
+	}
+	return d.sendInstruction(id, InstBackup, backupRestoreParams)
+}
+
+// BackupReady reports whether the on-board backup area holds a valid backup
+// (Backup Ready register, X-series: 147).
+func (d *Driver) BackupReady(id uint8, addr uint16) (bool, error) {
+	data, err := d.Read(id, addr, 1)
+	if err != nil && !isAlertOnly(err) {
+		return false, err
+	}
+	if len(data) != 1 {
+		return false, fmt.Errorf("invalid length: %d", len(data))
+	}
+	return data[0] == 1, nil
+}
+
+// === Host-side control table snapshots ===
+
+// SnapshotVersion is the current version of the snapshot file format
+const SnapshotVersion = 1
+
+// ControlTableRegion is a named, contiguous range of writable control table bytes
+type ControlTableRegion struct {
+	Name   string
+	Addr   uint16
+	Length uint16
+}
+
+// XSeriesConfigRegions are the writable configuration areas of X-series
+// (current-capable, e.g. XM430/XM540/XH430) control tables, in write order.
+// Runtime registers (Torque Enable, goals, Bus Watchdog) are left out.
+// SnapshotControlTable falls back to them for models missing from the registry.
+var XSeriesConfigRegions = []ControlTableRegion{
+	// EEPROM
+	{Name: "ID", Addr: 7, Length: 1},
+	{Name: "Baud_Rate", Addr: 8, Length: 1},
+	{Name: "Drive_Config", Addr: 9, Length: 4}, // Return Delay, Drive Mode, Operating Mode, Secondary ID
+	{Name: "Protocol_Type", Addr: 13, Length: 1},
+	{Name: "Homing_Threshold", Addr: 20, Length: 8}, // Homing Offset, Moving Threshold
+	{Name: "Limits", Addr: 31, Length: 9},           // Temperature, Voltage, PWM, Current Limit
+	{Name: "Motion_Limits", Addr: 44, Length: 12},   // Velocity Limit, Max/Min Position Limit
+	{Name: "Shutdown", Addr: 63, Length: 1},
+	// RAM
+	{Name: "Status_Return_Level", Addr: 68, Length: 1},
+	{Name: "PID_Gains", Addr: 76, Length: 10},        // Velocity I/P, Position D/I/P
+	{Name: "Feedforward_Gains", Addr: 88, Length: 4}, // Feedforward 2nd/1st
+	{Name: "Profile", Addr: 108, Length: 8},          // Profile Acceleration/Velocity
+	{Name: "Indirect_Address_1", Addr: 168, Length: 56},
+	{Name: "Indirect_Address_29", Addr: 578, Length: 56},
+}
+
+// maxRegionLength caps merged regions so each fits in one read, including on
+// Protocol 1.0 where the read length is a single byte
+const maxRegionLength = 128
+
+// runtimeItemPrefixes name writable items that command the motor rather than
+// configure it; snapshots leave them out
+var runtimeItemPrefixes = []string{
+	"Torque_Enable", "LED", "Goal_", "Bus_Watchdog", "Indirect_Data_", "External_Port_Data_",
+}
+
+func isRuntimeItem(name string) bool {
+	for _, p := range runtimeItemPrefixes {
+		if strings.HasPrefix(name, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// ConfigRegions returns the writable EEPROM and RAM configuration of the
+// model, in address order. Adjacent items in the same memory area are merged
+// into one region named after its first item; ID and BusSettingItems always
+// get a region of their own.
+func (m *ModelDefinition) ConfigRegions() []ControlTableRegion {
+	items := make([]ControlItem, 0, len(m.Items))
+	for _, it := range m.Items {
+		if it.Access == AccessReadWrite && !isRuntimeItem(it.Name) {
+			items = append(items, it)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Addr < items[j].Addr })
+
+	standalone := func(name string) bool {
+		if name == "ID" {
+			return true
+		}
+		for _, bus := range BusSettingItems {
+			if name == bus {
+				return true
+			}
+		}
+		return false
+	}
+
+	var regions []ControlTableRegion
+	var prev ControlItem
+	for i, it := range items {
+		if i > 0 && !standalone(it.Name) && !standalone(prev.Name) &&
+			it.Memory == prev.Memory && it.Addr == prev.Addr+uint16(prev.Size) {
+			last := &regions[len(regions)-1]
+			if last.Length+uint16(it.Size) <= maxRegionLength {
+				last.Length += uint16(it.Size)
+				prev = it
+				continue
+			}
+		}
+		regions = append(regions, ControlTableRegion{Name: it.Name, Addr: it.Addr, Length: uint16(it.Size)})
+		prev = it
+	}
+	return regions
+}
+
+// BusSettingItems change how a motor talks on the bus. Once one of them is
+// written, later packets go out at settings the motor no longer answers to (or
+// wait for a status packet it no longer sends), so ApplySnapshot never writes
+// them. Change them with WriteItem after the rest of the snapshot is applied.
+var BusSettingItems = []string{"Baud_Rate", "Protocol_Type", "Status_Return_Level"}
+
+// xSeriesBusSettings are the bus setting addresses used for models missing
+// from the registry
+var xSeriesBusSettings = map[uint16]bool{8: true, 13: true, 68: true}
+
+// busSettingAddrs returns the control table bytes holding BusSettingItems
+func busSettingAddrs(model uint16) map[uint16]bool {
+	def, ok := LookupModel(model)
+	if !ok {
+		return xSeriesBusSettings
+	}
+	addrs := make(map[uint16]bool)
+	for _, name := range BusSettingItems {
+		if it, ok := def.Item(name); ok {
+			for i := uint16(0); i < uint16(it.Size); i++ {
+				addrs[it.Addr+i] = true
+			}
+		}
+	}
+	return addrs
+}
+
+// SnapshotBlock holds the bytes read from one control table region
+type SnapshotBlock struct {
+	Name string `json:"name"`
+	Addr uint16 `json:"addr"`
+	Data string `json:"data"` // Hex-encoded
+}
+
+// ControlTableSnapshot is a host-side copy of a motor's configuration.
+// It can be saved as JSON and written back to the same or a replacement motor
+// of the same model, e.g. to clone a tuned joint onto a new servo.
+type ControlTableSnapshot struct {
+	Version     int             `json:"version"`
+	ModelNumber uint16          `json:"model_number"`
+	ID          uint8           `json:"id"`
+	Created     time.Time       `json:"created"`
+	Blocks      []SnapshotBlock `json:"blocks"`
+}
+
+// SnapshotControlTable reads the given regions of a motor into a snapshot.
+// With nil regions it reads the model's ConfigRegions from the registry, or
+// XSeriesConfigRegions if the model is unknown.
+func (d *Driver) SnapshotControlTable(id uint8, regions []ControlTableRegion) (*ControlTableSnapshot, error) {
+	model, err := d.Ping(id)
+	if err != nil {
+		return nil, fmt.Errorf("ping failed: %w", err)
+	}
+	if regions == nil {
+		if def, ok := LookupModel(model); ok {
+			regions = def.ConfigRegions()
+		} else {
+			regions = XSeriesConfigRegions
+		}
+	}
+
+	snap := &ControlTableSnapshot{
+		Version:     SnapshotVersion,
+		ModelNumber: model,
+		ID:          id,
+		Created:     time.Now(),
+		Blocks:      make([]SnapshotBlock, 0, len(regions)),
+	}
+
+	for _, r := range regions {
+		data, err := d.Read(id, r.Addr, r.Length)
+		if err != nil && !isAlertOnly(err) {
+			return nil, fmt.Errorf("read %s (addr %d) failed: %w", r.Name, r.Addr, err)
+		}
+		if len(data) != int(r.Length) {
+			return nil, fmt.Errorf("read %s: invalid length %d (expected %d)", r.Name, len(data), r.Length)
+		}
+		snap.Blocks = append(snap.Blocks, SnapshotBlock{
+			Name: r.Name,
+			Addr: r.Addr,
+			Data: hex.EncodeToString(data),
+		})
+	}
+
+	return snap, nil
+}
+
+// ApplySnapshot writes a snapshot back to the motor with the given ID.
+// The motor must be the same model and have torque disabled (EEPROM writes).
+// Blocks named in skip are not written; pass "ID" to keep the target's ID when
+// cloning onto a replacement servo. BusSettingItems are left out of every
+// block, and ID is written last.
+func (d *Driver) ApplySnapshot(id uint8, snap *ControlTableSnapshot, skip ...string) error {
+	if snap.Version != SnapshotVersion {
+		return fmt.Errorf("unsupported snapshot version %d", snap.Version)
+	}
+
+	model, err := d.Ping(id)
+	if err != nil {
+		return fmt.Errorf("ping failed: %w", err)
+	}
+	if model != snap.ModelNumber {
+		return fmt.Errorf("model mismatch: motor %d is model %d, snapshot is model %d", id, model, snap.ModelNumber)
+	}
+
+	skipped := make(map[string]bool, len(skip))
+	for _, name := range skip {
+		skipped[name] = true
+	}
+
+	busAddrs := busSettingAddrs(model)
+
+	// Write ID last: later blocks still address the motor by its current ID
+	var idBlock *SnapshotBlock
+	for i, b := range snap.Blocks {
+		if skipped[b.Name] {
+			continue
+		}
+		if b.Name == "ID" {
+			idBlock = &snap.Blocks[i]
+			continue
+		}
+		if err := d.writeSnapshotBlock(id, b, busAddrs); err != nil {
+			return err
+		}
+	}
+	if idBlock != nil {
+		return d.writeSnapshotBlock(id, *idBlock, busAddrs)
+	}
+	return nil
+}
+
+// writeSnapshotBlock writes a block, split around the excluded addresses
+func (d *Driver) writeSnapshotBlock(id uint8, b SnapshotBlock, exclude map[uint16]bool) error {
+	data, err := hex.DecodeString(b.Data)
+	if err != nil {
+		return fmt.Errorf("block %s: invalid data: %v", b.Name, err)
+	}
+	start := 0
+	for i := 0; i <= len(data); i++ {
+		if i < len(data) && !exclude[b.Addr+uint16(i)] {
+			continue
+		}
+		if i > start {
+			addr := b.Addr + uint16(start)
+			if err := d.Write(id, addr, data[start:i]); err != nil && !isAlertOnly(err) {
+				return fmt.Errorf("write %s (addr %d) failed: %w", b.Name, addr, err)
+			}
+		}
+		start = i + 1
+	}
+	return nil
+}
+
+// WriteSnapshotFile saves a snapshot as indented JSON
+func WriteSnapshotFile(path string, snap *ControlTableSnapshot) error {
+	data, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// ReadSnapshotFile loads a snapshot saved with WriteSnapshotFile
+func ReadSnapshotFile(path string) (*ControlTableSnapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var snap ControlTableSnapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, fmt.Errorf("invalid snapshot file: %v", err)
+	}
+	if snap.Version != SnapshotVersion {
+		return nil, fmt.Errorf("unsupported snapshot version %d", snap.Version)
+	}
+	return &snap, nil
+}
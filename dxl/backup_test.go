@@ -0,0 +1,205 @@
+package dxl
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+)
+
+func TestDriverBackupControlTable(t *testing.T) {
+	mock := NewMockSerialPort()
+	driver := NewDriver(mock)
+
+	mock.SetResponse(buildStatusPacket(1, 0, nil))
+	if err := driver.BackupControlTable(1); err != nil {
+		t.Fatalf("BackupControlTable failed: %v", err)
+	}
+	written := mock.GetWritten()
+	expected := []byte{InstBackup, 0x01, 'C', 'T', 'R', 'L'}
+	if !bytes.Equal(written[7:len(written)-2], expected) {
+		t.Errorf("Backup packet mismatch: got %X, want %X", written[7:len(written)-2], expected)
+	}
+}
+
+func TestDriverRestoreControlTable(t *testing.T) {
+	mock := NewMockSerialPort()
+	driver := NewDriver(mock)
+
+	mock.SetResponse(buildStatusPacket(1, 0, nil))
+	if err := driver.RestoreControlTable(1); err != nil {
+		t.Fatalf("RestoreControlTable failed: %v", err)
+	}
+	if written := mock.GetWritten(); written[8] != 0x02 {
+		t.Errorf("Expected restore mode 0x02, got %02X", written[8])
+	}
+}
+
+func TestDriverBackupReady(t *testing.T) {
+	mock := NewMockSerialPort()
+	driver := NewDriver(mock)
+
+	mock.SetResponse(buildStatusPacket(1, 0, []byte{1}))
+	ready, err := driver.BackupReady(1, 147)
+	if err != nil || !ready {
+		t.Errorf("BackupReady: got %v, %v", ready, err)
+	}
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	regions := []ControlTableRegion{
+		{Name: "ID", Addr: 7, Length: 1},
+		{Name: "PID_Gains", Addr: 76, Length: 4},
+	}
+	pingResponse := buildStatusPacket(1, 0, []byte{0x1E, 0x04, 0x2D}) // Model 1054
+
+	mock := NewScriptedSerialPort(
+		pingResponse,
+		buildStatusPacket(1, 0, []byte{1}),
+		buildStatusPacket(1, 0, []byte{0x10, 0x00, 0x20, 0x00}),
+	)
+	driver := NewDriver(mock)
+
+	snap, err := driver.SnapshotControlTable(1, regions)
+	if err != nil {
+		t.Fatalf("SnapshotControlTable failed: %v", err)
+	}
+	if snap.ModelNumber != 1054 || len(snap.Blocks) != 2 {
+		t.Fatalf("Unexpected snapshot: %+v", snap)
+	}
+
+	// Save and reload
+	path := filepath.Join(t.TempDir(), "joint1.json")
+	if err := WriteSnapshotFile(path, snap); err != nil {
+		t.Fatalf("WriteSnapshotFile failed: %v", err)
+	}
+	loaded, err := ReadSnapshotFile(path)
+	if err != nil {
+		t.Fatalf("ReadSnapshotFile failed: %v", err)
+	}
+	if loaded.Blocks[1].Data != "10002000" {
+		t.Errorf("Block data mismatch: %s", loaded.Blocks[1].Data)
+	}
+
+	// Apply to a replacement motor with ID 5, keeping its ID
+	target := NewScriptedSerialPort(
+		buildStatusPacket(5, 0, []byte{0x1E, 0x04, 0x2D}),
+		buildStatusPacket(5, 0, nil),
+	)
+	if err := NewDriver(target).ApplySnapshot(5, loaded, "ID"); err != nil {
+		t.Fatalf("ApplySnapshot failed: %v", err)
+	}
+	written := target.GetWritten()
+	write := written[len(written)-16:] // Write to addr 76 with 4 data bytes
+	if write[4] != 5 || write[7] != InstWrite || write[8] != 76 {
+		t.Errorf("Expected gains write to ID 5, got %X", write)
+	}
+}
+
+func TestApplySnapshotModelMismatch(t *testing.T) {
+	snap := &ControlTableSnapshot{Version: SnapshotVersion, ModelNumber: 1020}
+	mock := NewScriptedSerialPort(buildStatusPacket(1, 0, []byte{0x1E, 0x04, 0x2D}))
+
+	if err := NewDriver(mock).ApplySnapshot(1, snap); err == nil {
+		t.Error("Expected model mismatch error")
+	}
+}
+
+func TestApplySnapshotKeepsBusSettings(t *testing.T) {
+	// Snapshot of an XM430-W350 at 1 Mbps (Baud_Rate 3), Protocol 2 and no
+	// status packets for writes (Status_Return_Level 1); the target runs at
+	// 57600 with the default status return level
+	snap := &ControlTableSnapshot{
+		Version:     SnapshotVersion,
+		ModelNumber: 1020,
+		ID:          1,
+		Blocks: []SnapshotBlock{
+			{Name: "ID", Addr: 7, Data: "01"},
+			{Name: "Baud_Rate", Addr: 8, Data: "03"},
+			{Name: "Drive_Config", Addr: 9, Data: "0a00030002"}, // Protocol_Type inside the block
+			{Name: "Status_Return_Level", Addr: 68, Data: "01"},
+			{Name: "PID_Gains", Addr: 76, Data: "10002000"},
+		},
+	}
+
+	target := NewScriptedSerialPort(
+		buildStatusPacket(5, 0, []byte{0xFC, 0x03, 0x2D}), // Model 1020
+		buildStatusPacket(5, 0, nil),
+		buildStatusPacket(5, 0, nil),
+		buildStatusPacket(5, 0, nil),
+	)
+	if err := NewDriver(target).ApplySnapshot(5, snap); err != nil {
+		t.Fatalf("ApplySnapshot failed: %v", err)
+	}
+
+	// Collect the address and length of every write
+	type write struct {
+		addr uint16
+		n    int
+	}
+	var writes []write
+	buf := target.GetWritten()
+	for len(buf) >= 7 {
+		n := 7 + (int(buf[5]) | int(buf[6])<<8)
+		pkt := buf[:n]
+		buf = buf[n:]
+		if pkt[7] == InstWrite {
+			writes = append(writes, write{uint16(pkt[8]) | uint16(pkt[9])<<8, n - 12})
+		}
+	}
+
+	want := []write{{9, 4}, {76, 4}, {7, 1}}
+	if len(writes) != len(want) {
+		t.Fatalf("Expected writes %v, got %v", want, writes)
+	}
+	for i := range want {
+		if writes[i] != want[i] {
+			t.Errorf("Write %d: expected %v, got %v", i, want[i], writes[i])
+		}
+	}
+}
+
+func TestSnapshotRegistryRegions(t *testing.T) {
+	def, _ := LookupModel(1020) // XM430-W350
+	regions := def.ConfigRegions()
+
+	responses := [][]byte{buildStatusPacket(1, 0, []byte{0xFC, 0x03, 0x2D})}
+	for _, r := range regions {
+		responses = append(responses, buildStatusPacket(1, 0, make([]byte, r.Length)))
+	}
+	snap, err := NewDriver(NewScriptedSerialPort(responses...)).SnapshotControlTable(1, nil)
+	if err != nil {
+		t.Fatalf("SnapshotControlTable failed: %v", err)
+	}
+	if len(snap.Blocks) != len(regions) {
+		t.Fatalf("Expected %d blocks, got %d", len(regions), len(snap.Blocks))
+	}
+
+	names := make(map[string]bool)
+	for _, b := range snap.Blocks {
+		names[b.Name] = true
+	}
+	for _, name := range []string{"ID", "Baud_Rate", "Protocol_Type", "Status_Return_Level", "Homing_Offset", "Velocity_I_Gain", "Indirect_Address_1"} {
+		if !names[name] {
+			t.Errorf("Missing block %s in %v", name, regions)
+		}
+	}
+	for _, r := range regions {
+		if it, ok := def.Item("Goal_Position"); ok && r.Addr <= it.Addr && it.Addr < r.Addr+r.Length {
+			t.Errorf("Region %s covers Goal_Position", r.Name)
+		}
+	}
+}
+
+func TestSnapshotUnknownModelRegions(t *testing.T) {
+	responses := [][]byte{buildStatusPacket(1, 0, []byte{0x1E, 0x04, 0x2D})} // Model 1054, not registered
+	for _, r := range XSeriesConfigRegions {
+		responses = append(responses, buildStatusPacket(1, 0, make([]byte, r.Length)))
+	}
+	snap, err := NewDriver(NewScriptedSerialPort(responses...)).SnapshotControlTable(1, nil)
+	if err != nil {
+		t.Fatalf("SnapshotControlTable failed: %v", err)
+	}
+	if len(snap.Blocks) != len(XSeriesConfigRegions) {
+		t.Errorf("Expected the fixed X-series regions, got %d blocks", len(snap.Blocks))
+	}
+}
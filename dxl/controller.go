@@ -2,9 +2,12 @@ package dxl
 
 import (
 	"context"
+	"encoding/binary"
 	"fmt"
+	"math"
 	"runtime"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -14,9 +17,13 @@ type Controller struct {
 	devicePort string
 	baudRate   int
 
-	// Channels for communication with the control loop
+	// Channels for communication with the control loop. Commands sent on
+	// CommandChan merge with those from Send (newest goal per motor wins).
 	CommandChan  chan []Command
 	FeedbackChan chan []Feedback
+	SafetyEvents chan SafetyEvent // Goals that hit a safety limit (oldest dropped when full)
+	FaultEvents  chan FaultEvent  // Detected faults and the reaction taken (oldest dropped when full)
+	StateEvents  chan StateChange // Controller state transitions (oldest dropped when full)
 
 	// Context for graceful shutdown
 	ctx    context.Context
@@ -24,13 +31,63 @@ type Controller struct {
 	wg     sync.WaitGroup
 
 	// Configuration
-	Model    MotorModel
-	MotorIDs []uint8 // List of motor IDs to control
+	Model       MotorModel // Default layout; leave zero to configure from the model registry at Start
+	MotorIDs    []uint8    // List of motor IDs to control
+	ScanOnStart bool       // Replace MotorIDs with the IDs found by a bus scan in Start
 
 	// Internal State
-	mu               sync.RWMutex // Protects shared state
-	activeGoalAddr   uint16
-	useSyncReadWrite bool // Enable sync read/write for better performance
+	busMu            sync.Mutex                 // Serializes driver access between the control loop and API calls
+	portClosed       bool                       // Set when shutdown closed the driver's port (under busMu)
+	mu               sync.RWMutex               // Protects shared state
+	opModes          map[uint8]uint8            // Per-motor operating mode (read at Start or set via SetOperatingMode)
+	goalAddrs        map[uint8]uint16           // Per-motor goal register, following the operating mode
+	motorModels      map[uint8]MotorModel       // Per-motor layouts (SetMotorModel or detected at Start)
+	manualModels     map[uint8]bool             // IDs whose layout was set with SetMotorModel
+	currentLimits    map[uint8]int64            // Current Limit register, read at Start
+	models           map[uint8]*ModelDefinition // Registry definitions of the motors found at Start
+	units            map[uint8]Units            // Per-motor unit scales for SI mode
+	siMode           bool                       // Commands and feedback use SI values
+	stateFields      StateField                 // Fields polled each cycle
+	indirectStart    int                        // First Indirect Address slot for feedback, 0 = span read
+	statePlans       map[uint8]*statePlan       // Per-motor read plans for stateFields; rebuilt when nil
+	indirectReady    bool                       // Indirect feedback table programmed at Start
+	seq              uint64                     // Feedback sequence number (control loop only)
+	useSyncReadWrite bool                       // Enable sync read/write for better performance
+	useFastSyncRead  bool                       // Use Fast Sync Read (single combined status packet)
+	period           time.Duration              // Control period, 0 = free-running
+	stats            loopStats                  // Loop timing (own lock)
+	commands         commandMailbox             // Pending goals, latest per motor (own lock)
+	safetyLimits     map[uint8]SafetyLimits     // Per-motor software envelope
+	safetyFaults     map[uint8]bool             // Motors with a latched SafetyFault
+	lastGoals        map[uint8]map[uint16]int64 // Last goal written per motor and register, for step limits
+	eventMu          sync.Mutex                 // Serializes stamping and sending SafetyEvents and FaultEvents
+
+	// Lifecycle and fault handling
+	state             atomic.Int32 // ControllerState
+	stateMu           sync.Mutex   // Serializes state transitions and their StateEvents
+	faultReactions    map[FaultClass]FaultReaction
+	commLossThreshold int
+	faultMu           sync.Mutex              // Guards the fault tracking below
+	commFailures      map[uint8]int           // Consecutive read timeouts per motor
+	hwErrors          map[uint8]HardwareError // Last seen Hardware Error Status per motor
+	offline           map[uint8]bool          // Motors that stopped answering
+	lastSeen          map[uint8]time.Time     // Last successful read per motor
+	lastProbe         map[uint8]time.Time     // Last ping of an offline motor
+
+	// Bus watchdog
+	watchdog          time.Duration // Bus Watchdog timeout, 0 = off
+	watchdogIDs       []uint8       // Motors whose watchdog was armed at Start
+	lastWatchdogCheck time.Time     // Control loop only
+	lastRead          time.Time     // End of the previous feedback read (control loop only)
+
+	// Startup and shutdown
+	startup     StartupPolicy
+	shutdownCfg Shutdown
+
+	// Feedback publishing
+	subMu  sync.Mutex                      // Serializes Subscribe/Close
+	subs   atomic.Pointer[[]*Subscription] // Copy-on-write subscriber list
+	latest atomic.Pointer[[]Feedback]      // Most recent feedback (LatestState)
 }
 
 // MotorModel defines the Control Table addresses for a specific motor type
@@ -39,20 +96,46 @@ type MotorModel struct {
 	AddrGoalPosition    uint16
 	AddrGoalVelocity    uint16
 	AddrGoalPWM         uint16
+	AddrGoalCurrent     uint16
 	AddrPresentPosition uint16
 	AddrOperatingMode   uint16
+
+	AddrHardwareErrorStatus   uint16
+	AddrRegisteredInstruction uint16
 }
 
 // Command represents a write command to a motor
 type Command struct {
 	ID    uint8
 	Value uint32
+	SI    float64 // Goal in rad, rad/s, PWM percent or A (by operating mode); used instead of Value in SI mode
+
+	// WithCurrent also writes Goal Current this cycle, e.g. to limit torque
+	// in current-based position mode. Current is raw (signed), CurrentSI in
+	// amperes is used instead in SI mode.
+	WithCurrent bool
+	Current     int16
+	CurrentSI   float64
+
+	// Timestamp is when the goal was computed (optional). Timestamped goals
+	// never replace a newer pending one and can expire (SetCommandMaxAge).
+	Timestamp time.Time
 }
 
 // Feedback represents a read value from a motor
 type Feedback struct {
 	ID    uint8
-	Value uint32
+	Value uint32     // Raw Present Position (kept for single-value users)
+	SI    float64    // Present position in rad; set in SI mode
+	State MotorState // All polled fields (see SetStateFields)
+	// StateSI holds the polled fields in SI units; set in SI mode
+	StateSI MotorStateSI
+
+	Timestamp time.Time // Host time when the read completed
+	Seq       uint64    // Control cycle number, shared by all motors of one read
+
+	// Error is set when the motor could not be read. An alert-only
+	// *StatusError (hardware error flagged) still comes with a valid State.
 	Error error
 }
 
@@ -64,21 +147,43 @@ var (
 		AddrGoalPosition:    116,
 		AddrGoalVelocity:    104,
 		AddrGoalPWM:         100,
+		AddrGoalCurrent:     102,
 		AddrPresentPosition: 132,
 		AddrOperatingMode:   11,
+
+		AddrHardwareErrorStatus:   70,
+		AddrRegisteredInstruction: 69,
 	}
-	// Pro-Series (H54, H42, etc.)
+	// Legacy Pro-Series (H54, H42, M54, M42, L54). These have no Goal PWM
+	// or Goal Current (Goal Torque works differently).
 	ModelProSeries = MotorModel{
-		AddrTorqueEnable:    562, // Example, verify for specific PRO model
+		AddrTorqueEnable:    562,
 		AddrGoalPosition:    596,
-		AddrGoalVelocity:    600, // Check Manual
-		AddrGoalPWM:         584, // Check Manual
+		AddrGoalVelocity:    600,
 		AddrPresentPosition: 611,
-		AddrOperatingMode:   11, // PRO Series often shares 11 too, need check
+		AddrOperatingMode:   11,
+
+		AddrHardwareErrorStatus:   892,
+		AddrRegisteredInstruction: 890,
+	}
+	// PRO+ / P-Series (H54P, H42P, M54P, M42P)
+	ModelProPlusSeries = MotorModel{
+		AddrTorqueEnable:    512,
+		AddrGoalPosition:    564,
+		AddrGoalVelocity:    552,
+		AddrGoalPWM:         548,
+		AddrGoalCurrent:     550,
+		AddrPresentPosition: 580,
+		AddrOperatingMode:   11,
+
+		AddrHardwareErrorStatus:   518,
+		AddrRegisteredInstruction: 517,
 	}
-	// PRO+ Series usually similar to X-Series layout or specific
 )
 
+// RebootTimeout bounds how long RecoverMotor waits for a rebooted motor to answer pings
+const RebootTimeout = 2 * time.Second
+
 const (
 	OpModeCurrent          = 0
 	OpModeVelocity         = 1
@@ -88,6 +193,9 @@ const (
 	OpModePWM              = 16
 )
 
+// NewController creates a controller for the motors on devicePort.
+// Pass a zero MotorModel to take the addresses from the model registry
+// once Start has pinged the motors.
 func NewController(devicePort string, baudRate int, model MotorModel) *Controller {
 	ctx, cancel := context.WithCancel(context.Background())
 	return &Controller{
@@ -95,11 +203,18 @@ func NewController(devicePort string, baudRate int, model MotorModel) *Controlle
 		baudRate:         baudRate,
 		CommandChan:      make(chan []Command, 1),
 		FeedbackChan:     make(chan []Feedback, 100),
+		SafetyEvents:     make(chan SafetyEvent, 64),
+		FaultEvents:      make(chan FaultEvent, 64),
+		StateEvents:      make(chan StateChange, 64),
 		ctx:              ctx,
 		cancel:           cancel,
 		Model:            model,
 		MotorIDs:         []uint8{1}, // Default single motor
-		activeGoalAddr:   model.AddrGoalPosition, // Default Address
+		opModes:          make(map[uint8]uint8),
+		goalAddrs:        make(map[uint8]uint16),
+		motorModels:      make(map[uint8]MotorModel),
+		manualModels:     make(map[uint8]bool),
+		stateFields:      DefaultStateFields,
 		useSyncReadWrite: false, // Default to individual commands for single motor
 	}
 }
@@ -114,6 +229,24 @@ func (c *Controller) SetMotorIDs(ids []uint8) {
 	c.useSyncReadWrite = len(ids) > 1
 }
 
+// SetFastSyncRead enables the Fast Sync Read instruction for feedback reads.
+// All motors then answer in one combined status packet, which removes the
+// per-motor response wait. Only applies in sync mode (multiple motors) and
+// requires firmware that supports Fast Sync Read.
+// Thread-safe: can be called while control loop is running
+func (c *Controller) SetFastSyncRead(enabled bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.useFastSyncRead = enabled
+}
+
+// isFastSyncRead returns whether Fast Sync Read is enabled (thread-safe)
+func (c *Controller) isFastSyncRead() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.useFastSyncRead
+}
+
 // getMotorIDs returns a copy of motor IDs (thread-safe)
 func (c *Controller) getMotorIDs() []uint8 {
 	c.mu.RLock()
@@ -130,25 +263,171 @@ func (c *Controller) isSyncMode() bool {
 	return c.useSyncReadWrite
 }
 
-// getActiveGoalAddr returns the active goal address (thread-safe)
-func (c *Controller) getActiveGoalAddr() uint16 {
+// SetMotorModel assigns a register layout to one motor, for buses that mix
+// motor families. Motors without one use Model, or the layout detected from
+// the registry at Start. Start fails if the detected model does not match.
+// Call before Start.
+func (c *Controller) SetMotorModel(id uint8, model MotorModel) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.motorModels == nil {
+		c.motorModels = make(map[uint8]MotorModel)
+		c.manualModels = make(map[uint8]bool)
+	}
+	c.motorModels[id] = model
+	c.manualModels[id] = true
+	c.statePlans = nil
+}
+
+// ModelFor returns the register layout used for a motor (thread-safe)
+func (c *Controller) ModelFor(id uint8) MotorModel {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.modelForLocked(id)
+}
+
+// modelForLocked is ModelFor for callers already holding mu
+func (c *Controller) modelForLocked(id uint8) MotorModel {
+	if m, ok := c.motorModels[id]; ok {
+		return m
+	}
+	return c.Model
+}
+
+// goalAddrFor returns the goal register of a motor: the one matching its
+// operating mode, or Goal Position if the mode is unknown (thread-safe)
+func (c *Controller) goalAddrFor(id uint8) uint16 {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
-	return c.activeGoalAddr
+	if addr, ok := c.goalAddrs[id]; ok {
+		return addr
+	}
+	return c.modelForLocked(id).AddrGoalPosition
+}
+
+// goalAddrForMode returns the goal register a motor listens to in a mode
+func (c *Controller) goalAddrForMode(id uint8, mode uint8) (uint16, error) {
+	m := c.ModelFor(id)
+	var addr uint16
+	switch mode {
+	case OpModeVelocity:
+		addr = m.AddrGoalVelocity
+	case OpModePWM:
+		addr = m.AddrGoalPWM
+	case OpModePosition, OpModeExtendedPosition, OpModeCurrentBasedPos:
+		addr = m.AddrGoalPosition
+	case OpModeCurrent:
+		addr = m.AddrGoalCurrent
+	default:
+		return 0, fmt.Errorf("unknown operating mode %d", mode)
+	}
+	if addr == 0 {
+		return 0, fmt.Errorf("motor model has no goal register for operating mode %d", mode)
+	}
+	return addr, nil
+}
+
+// setMode records a motor's operating mode and routes its goals accordingly
+func (c *Controller) setMode(id uint8, mode uint8) error {
+	addr, err := c.goalAddrForMode(id, mode)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.opModes == nil {
+		c.opModes = make(map[uint8]uint8)
+	}
+	if c.goalAddrs == nil {
+		c.goalAddrs = make(map[uint8]uint16)
+	}
+	c.opModes[id] = mode
+	c.goalAddrs[id] = addr
+	return nil
+}
+
+// OperatingMode returns the operating mode of a motor, if known
+func (c *Controller) OperatingMode(id uint8) (uint8, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	mode, ok := c.opModes[id]
+	return mode, ok
+}
+
+// loadOperatingModes reads each motor's Operating Mode so goals are routed
+// to the right register even for motors configured elsewhere
+func (c *Controller) loadOperatingModes(ids []uint8) {
+	for _, id := range ids {
+		c.busMu.Lock()
+		data, err := c.driver.Read(id, c.ModelFor(id).AddrOperatingMode, 1)
+		c.busMu.Unlock()
+		if (err != nil && !isAlertOnly(err)) || len(data) != 1 {
+			fmt.Printf("Warning: could not read operating mode for ID %d: %v\n", id, err)
+			continue
+		}
+		if err := c.setMode(id, data[0]); err != nil {
+			fmt.Printf("Warning: motor %d: %v, using Goal Position\n", id, err)
+		}
+	}
 }
 
 // Start spawns the control loop goroutine
 func (c *Controller) Start() error {
+	if _, ok := c.setState(StateStarting, nil, StateIdle); !ok {
+		return fmt.Errorf("cannot start: controller is %v", c.State())
+	}
+	if err := c.start(); err != nil {
+		c.setState(StateIdle, err)
+		return err
+	}
+	c.setState(StateRunning, nil, StateStarting)
+
+	// Start the control loop in a separate goroutine
+	c.stats.reset(c.getPeriod())
+	c.wg.Add(1)
+	go c.controlLoop()
+
+	return nil
+}
+
+// start opens the port and configures the motors for Start
+func (c *Controller) start() error {
 	// 1. Open Serial Port (Native Windows)
 	sp, err := OpenSerial(c.devicePort, c.baudRate)
 	if err != nil {
 		return fmt.Errorf("failed to open serial port: %v", err)
 	}
 
-	c.driver = NewDriver(sp)
+	driver := NewDriver(sp)
+	driver.BaudRate = c.baudRate
+	c.busMu.Lock() // EStop may already be reading it
+	c.driver = driver
+	c.portClosed = false
+	c.busMu.Unlock()
+
+	// 2. Discover motors on the bus if requested
+	if c.ScanOnStart {
+		fmt.Println("Scanning bus...")
+		devices, err := c.driver.Scan(c.ctx)
+		if err != nil {
+			sp.Close()
+			return fmt.Errorf("bus scan failed: %v", err)
+		}
+		if len(devices) == 0 {
+			sp.Close()
+			return fmt.Errorf("bus scan found no motors. Check Power/Baudrate")
+		}
+		ids := make([]uint8, len(devices))
+		for i, dev := range devices {
+			fmt.Printf("Found Motor ID %d (Model %d, Firmware %d)\n", dev.ID, dev.ModelNumber, dev.Firmware)
+			ids[i] = dev.ID
+		}
+		c.SetMotorIDs(ids)
+	}
 
-	// 2. Ping and enable torque for all configured motors
+	// 3. Ping all configured motors and check them against the registry
 	motorIDs := c.getMotorIDs()
+	found := make(map[uint8]uint16, len(motorIDs))
 	for _, id := range motorIDs {
 		fmt.Printf("Pinging Motor ID %d...\n", id)
 		model, err := c.driver.Ping(id)
@@ -157,24 +436,253 @@ func (c *Controller) Start() error {
 			return fmt.Errorf("ping failed for ID %d: %v. Check Power/ID/Baudrate", id, err)
 		}
 		fmt.Printf("Motor ID %d Found! Model Number: %d\n", id, model)
+		found[id] = model
+	}
+	if err := c.configureModels(motorIDs, found); err != nil {
+		sp.Close()
+		return err
+	}
 
-		if err := c.enableTorque(id); err != nil {
-			sp.Close()
-			return fmt.Errorf("failed to enable torque for ID %d: %v", id, err)
+	if c.isSIMode() {
+		c.loadHomingOffsets(motorIDs)
+	}
+	c.loadOperatingModes(motorIDs)
+	c.loadCurrentLimits(motorIDs)
+	if err := c.checkPositionLimitRegisters(motorIDs); err != nil {
+		sp.Close()
+		return err
+	}
+	if err := c.prepareStatePlan(motorIDs); err != nil {
+		sp.Close()
+		return err
+	}
+
+	// 4. Enable torque (startup policy) and arm the bus watchdog
+	if err := c.enableAndArm(motorIDs); err != nil {
+		sp.Close()
+		return err
+	}
+
+	return nil
+}
+
+// configureModels looks up the pinged model numbers in the registry and
+// gives each known motor its own layout, so one bus can mix families.
+// A zero Model is filled in from the first known motor. Layouts configured
+// explicitly (SetMotorModel, or a non-zero Model for motors without their
+// own) must match what the registry says the motor is.
+func (c *Controller) configureModels(ids []uint8, found map[uint8]uint16) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	auto := c.Model == (MotorModel{})
+	c.models = make(map[uint8]*ModelDefinition, len(ids))
+	c.units = make(map[uint8]Units, len(ids))
+	if c.motorModels == nil {
+		c.motorModels = make(map[uint8]MotorModel)
+	}
+	c.statePlans = nil
+
+	var first *ModelDefinition
+	for _, id := range ids {
+		number := found[id]
+		def, ok := LookupModel(number)
+		if !ok {
+			if auto && !c.manualModels[id] {
+				return fmt.Errorf("motor %d has unknown model number %d; set Controller.Model or register the model", id, number)
+			}
+			fmt.Printf("Warning: motor %d has unknown model number %d, using configured addresses\n", id, number)
+			continue
+		}
+		detected := def.MotorModel()
+		switch {
+		case c.manualModels[id]:
+			if !compatibleLayout(c.motorModels[id], detected) {
+				return fmt.Errorf("motor %d is %s, whose control table does not match the model set for it", id, def.Name)
+			}
+		case !auto && !compatibleLayout(c.Model, detected):
+			return fmt.Errorf("motor %d is %s, whose control table does not match the configured model; use SetMotorModel for mixed buses", id, def.Name)
+		default:
+			c.motorModels[id] = detected
+		}
+
+		c.models[id] = def
+		c.units[id] = def.Units()
+		if c.driver != nil {
+			c.driver.SetModel(id, def)
+		}
+		if first == nil {
+			first = def
 		}
 	}
 
-	// Start the control loop in a separate goroutine
-	c.wg.Add(1)
-	go c.controlLoop()
+	if auto && first != nil {
+		c.Model = first.MotorModel()
+		fmt.Printf("Configured control table from %s (model %d)\n", first.Name, first.Number)
+	}
+	return nil
+}
 
+// SetSIMode switches commands and feedback between raw register values and
+// SI units. In SI mode Command.SI is converted per motor to the active goal
+// register, Feedback.SI carries the present position in radians and
+// Feedback.StateSI every polled field in SI units.
+// Thread-safe: can be called while control loop is running
+func (c *Controller) SetSIMode(enabled bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.siMode = enabled
+}
+
+// isSIMode returns whether SI mode is enabled (thread-safe)
+func (c *Controller) isSIMode() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.siMode
+}
+
+// UnitsFor returns the unit scales of a motor. Motors without a registry
+// definition use XSeriesUnits.
+func (c *Controller) UnitsFor(id uint8) Units {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if u, ok := c.units[id]; ok {
+		return u
+	}
+	return XSeriesUnits
+}
+
+// loadHomingOffsets reads each motor's Homing Offset into its Units
+func (c *Controller) loadHomingOffsets(ids []uint8) {
+	for _, id := range ids {
+		if _, ok := c.MotorDefinition(id); !ok {
+			continue
+		}
+		offset, err := c.ReadItem(id, "Homing_Offset")
+		if err != nil {
+			fmt.Printf("Warning: could not read homing offset for ID %d: %v\n", id, err)
+			continue
+		}
+		c.mu.Lock()
+		u := c.units[id]
+		u.HomingOffset = offset
+		c.units[id] = u
+		c.mu.Unlock()
+	}
+}
+
+// siToRaw converts an SI goal to the raw value of the active goal register.
+// Goals that do not fit the register are rejected rather than wrapped.
+func (c *Controller) siToRaw(id uint8, goalAddr uint16, v float64) (uint32, error) {
+	raw := c.siGoal(id, goalAddr, v)
+	if err := checkGoalRange(id, raw, c.goalSize(id, goalAddr)); err != nil {
+		return 0, err
+	}
+	return uint32(int32(raw)), nil
+}
+
+// checkGoalRange rejects a goal outside the signed range of its size-byte
+// register
+func checkGoalRange(id uint8, goal int64, size int) error {
+	lo, hi := int64(math.MinInt32), int64(math.MaxInt32)
+	if size == 2 {
+		lo, hi = math.MinInt16, math.MaxInt16
+	}
+	if goal < lo || goal > hi {
+		return fmt.Errorf("motor %d: goal %d is out of range for a %d-byte register", id, goal, size)
+	}
 	return nil
 }
 
+// siGoal converts an SI goal to a raw register value, before narrowing
+func (c *Controller) siGoal(id uint8, goalAddr uint16, v float64) int64 {
+	u := c.UnitsFor(id)
+	m := c.ModelFor(id)
+	var raw int64
+	switch goalAddr {
+	case m.AddrGoalVelocity:
+		raw = u.RadPerSecToVelocity(v)
+	case m.AddrGoalPWM:
+		raw = u.PercentToPWM(v)
+	case m.AddrGoalCurrent:
+		raw = u.AmpsToCurrent(v)
+	default:
+		raw = u.RadToPosition(v)
+	}
+	return raw
+}
+
+// fillSI converts the polled fields to SI units
+func (c *Controller) fillSI(feedbacks []Feedback) {
+	for i := range feedbacks {
+		fb := &feedbacks[i]
+		u := c.UnitsFor(fb.ID)
+		st := fb.State
+		if st.Has(FieldPosition) {
+			fb.StateSI.Position = u.PositionToRad(int64(st.Position))
+			fb.SI = fb.StateSI.Position
+		}
+		if st.Has(FieldVelocity) {
+			fb.StateSI.Velocity = u.VelocityToRadPerSec(int64(st.Velocity))
+		}
+		if st.Has(FieldCurrent) && c.hasCurrentSensing(fb.ID) {
+			fb.StateSI.Current = u.CurrentToAmps(int64(st.Current))
+		}
+		if st.Has(FieldPWM) {
+			fb.StateSI.PWM = u.PWMToPercent(int64(st.PWM))
+		}
+		if st.Has(FieldInputVoltage) {
+			fb.StateSI.Voltage = u.VoltageToVolts(int64(st.InputVoltage))
+		}
+		if st.Has(FieldTemperature) {
+			fb.StateSI.Temperature = u.TemperatureToCelsius(int64(st.Temperature))
+		}
+	}
+}
+
+// hasCurrentSensing reports whether FieldCurrent is read from Present
+// Current rather than Present Load. Motors without a registry definition use
+// the X-series layout, which has it.
+func (c *Controller) hasCurrentSensing(id uint8) bool {
+	def, ok := c.MotorDefinition(id)
+	if !ok {
+		return true
+	}
+	_, ok = def.Item("Present_Current")
+	return ok
+}
+
+// MotorDefinition returns the registry definition of a motor found at Start
+func (c *Controller) MotorDefinition(id uint8) (*ModelDefinition, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	def, ok := c.models[id]
+	return def, ok
+}
+
+// ReadItem reads a control table item by name (see Driver.ReadItem)
+// Thread-safe: can be called while control loop is running
+func (c *Controller) ReadItem(id uint8, name string) (int64, error) {
+	c.busMu.Lock()
+	defer c.busMu.Unlock()
+	return c.driver.ReadItem(id, name)
+}
+
+// WriteItem writes a control table item by name (see Driver.WriteItem)
+// Thread-safe: can be called while control loop is running
+func (c *Controller) WriteItem(id uint8, name string, value int64) error {
+	c.busMu.Lock()
+	defer c.busMu.Unlock()
+	return c.driver.WriteItem(id, name, value)
+}
+
 func (c *Controller) enableTorque(id uint8) error {
+	addr := c.ModelFor(id).AddrTorqueEnable
 	// Write 1 to proper address
-	fmt.Printf("Enabling Torque for ID %d at address %d...\n", id, c.Model.AddrTorqueEnable)
-	err := c.driver.Write(id, c.Model.AddrTorqueEnable, []byte{1})
+	fmt.Printf("Enabling Torque for ID %d at address %d...\n", id, addr)
+	c.busMu.Lock()
+	err := c.driver.Write(id, addr, []byte{1})
+	c.busMu.Unlock()
 	if err != nil {
 		return err
 	}
@@ -184,7 +692,9 @@ func (c *Controller) enableTorque(id uint8) error {
 
 	// Verify (optional - can be disabled if causing issues)
 	// Read 1 Byte
-	data, err := c.driver.Read(id, c.Model.AddrTorqueEnable, 1)
+	c.busMu.Lock()
+	data, err := c.driver.Read(id, addr, 1)
+	c.busMu.Unlock()
 	if err != nil {
 		// If read fails, assume write succeeded (some motors don't respond well to rapid read after write)
 		fmt.Printf("Warning: Could not verify torque enable (read error: %v), assuming success\n", err)
@@ -202,13 +712,22 @@ func (c *Controller) enableTorque(id uint8) error {
 }
 
 func (c *Controller) disableTorque(id uint8) error {
+	addr := c.ModelFor(id).AddrTorqueEnable
 	fmt.Printf("Disabling Torque for ID %d...\n", id)
-	return c.driver.Write(id, c.Model.AddrTorqueEnable, []byte{0})
+	c.busMu.Lock()
+	defer c.busMu.Unlock()
+	return c.driver.Write(id, addr, []byte{0})
 }
 
 // SetOperatingMode changes the control mode (Torque Disable -> Set Mode -> Torque Enable)
 // Common Modes: 1 (Velocity), 3 (Position), 16 (PWM)
+// Only this motor's goal register changes; other motors keep theirs.
 func (c *Controller) SetOperatingMode(id uint8, mode uint8) error {
+	if _, err := c.goalAddrForMode(id, mode); err != nil {
+		return err
+	}
+	m := c.ModelFor(id)
+
 	// 1. Disable Torque
 	if err := c.disableTorque(id); err != nil {
 		return fmt.Errorf("failed to disable torque: %v", err)
@@ -216,7 +735,10 @@ func (c *Controller) SetOperatingMode(id uint8, mode uint8) error {
 
 	// 2. Set Mode
 	fmt.Printf("Setting Operating Mode to %d for ID %d...\n", mode, id)
-	if err := c.driver.Write(id, c.Model.AddrOperatingMode, []byte{mode}); err != nil {
+	c.busMu.Lock()
+	err := c.driver.Write(id, m.AddrOperatingMode, []byte{mode})
+	c.busMu.Unlock()
+	if err != nil {
 		return fmt.Errorf("failed to set operating mode: %v", err)
 	}
 
@@ -225,27 +747,19 @@ func (c *Controller) SetOperatingMode(id uint8, mode uint8) error {
 	time.Sleep(1000 * time.Millisecond)
 
 	// Verify mode was actually set
-	data, err := c.driver.Read(id, c.Model.AddrOperatingMode, 1)
+	c.busMu.Lock()
+	data, err := c.driver.Read(id, m.AddrOperatingMode, 1)
+	c.busMu.Unlock()
 	if err != nil {
 		fmt.Printf("Warning: could not verify operating mode (read error: %v)\n", err)
 	} else if len(data) > 0 && data[0] != mode {
 		return fmt.Errorf("operating mode verification failed: wrote %d, read back %d", mode, data[0])
 	}
 
-	// Update Active Goal Address (thread-safe)
-	c.mu.Lock()
-	switch mode {
-	case OpModeVelocity:
-		c.activeGoalAddr = c.Model.AddrGoalVelocity
-	case OpModePWM:
-		c.activeGoalAddr = c.Model.AddrGoalPWM
-	case OpModePosition, OpModeExtendedPosition, OpModeCurrentBasedPos:
-		c.activeGoalAddr = c.Model.AddrGoalPosition
-	case OpModeCurrent:
-		fmt.Printf("Warning: Current mode not fully supported, using position address\n")
-		c.activeGoalAddr = c.Model.AddrGoalPosition
+	// Route this motor's goals to the new register (thread-safe)
+	if err := c.setMode(id, mode); err != nil {
+		return err
 	}
-	c.mu.Unlock()
 
 	// 3. Re-Enable Torque
 	if err := c.enableTorque(id); err != nil {
@@ -255,83 +769,524 @@ func (c *Controller) SetOperatingMode(id uint8, mode uint8) error {
 	return nil
 }
 
-// Stop signals the control loop to exit and waits for it to finish
+// ReadHardwareError reads and decodes the Hardware Error Status of a motor.
+// Use it after a *StatusError with Alert set to find out what went wrong.
+func (c *Controller) ReadHardwareError(id uint8) (HardwareError, error) {
+	addr := c.ModelFor(id).AddrHardwareErrorStatus
+	c.busMu.Lock()
+	defer c.busMu.Unlock()
+	return c.driver.ReadHardwareError(id, addr)
+}
+
+// RecoverMotor reboots a motor that latched a hardware error and restores its
+// state: the operating mode set through SetOperatingMode, the goal held at the
+// present position (position modes), torque enabled and the bus watchdog.
+// Returns the hardware error that was cleared, or 0 if the motor had none,
+// in which case nothing is done.
+// Torque stays off while the controller is e-stopped (ErrEStopped) or
+// faulted: use Reset, which recovers every motor.
+func (c *Controller) RecoverMotor(id uint8) (HardwareError, error) {
+	switch st := c.State(); st {
+	case StateIdle, StateRunning:
+	case StateEStopped:
+		return 0, fmt.Errorf("cannot recover motor %d: %w; use Reset", id, ErrEStopped)
+	default:
+		return 0, fmt.Errorf("cannot recover motor %d: controller is %v", id, st)
+	}
+	return c.recoverMotor(id)
+}
+
+// recoverMotor is RecoverMotor without the state check, for Reset
+func (c *Controller) recoverMotor(id uint8) (HardwareError, error) {
+	hwErr, err := c.ReadHardwareError(id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read hardware error: %w", err)
+	}
+	if hwErr == 0 {
+		return 0, nil
+	}
+
+	// 1. Reboot (clears the error latch, disables torque)
+	fmt.Printf("Motor %d hardware error: %v. Rebooting...\n", id, hwErr)
+	c.busMu.Lock()
+	err = c.driver.Reboot(id)
+	c.busMu.Unlock()
+	if err != nil && !isAlertOnly(err) {
+		return hwErr, fmt.Errorf("reboot failed: %w", err)
+	}
+
+	// 2. Wait until the motor answers again
+	if err := c.waitForMotor(id, RebootTimeout); err != nil {
+		return hwErr, err
+	}
+
+	// 3. Restore operating mode (EEPROM, normally survives the reboot)
+	m := c.ModelFor(id)
+	c.mu.RLock()
+	mode, hasMode := c.opModes[id]
+	c.mu.RUnlock()
+	if hasMode {
+		c.busMu.Lock()
+		data, err := c.driver.Read(id, m.AddrOperatingMode, 1)
+		if err == nil && len(data) == 1 && data[0] != mode {
+			err = c.driver.Write(id, m.AddrOperatingMode, []byte{mode})
+		}
+		c.busMu.Unlock()
+		if err != nil {
+			return hwErr, fmt.Errorf("failed to restore operating mode: %w", err)
+		}
+	}
+
+	// 4. Hold the current position so the motor does not jump when torque returns
+	if err := c.holdPosition(id); err != nil {
+		return hwErr, err
+	}
+
+	// 5. Re-enable torque
+	if err := c.enableTorque(id); err != nil {
+		return hwErr, fmt.Errorf("failed to enable torque: %w", err)
+	}
+
+	// 6. The reboot cleared the bus watchdog
+	if err := c.rearmWatchdog(id); err != nil {
+		return hwErr, fmt.Errorf("failed to re-arm bus watchdog: %w", err)
+	}
+	return hwErr, nil
+}
+
+// holdPosition copies Present Position to Goal Position of a motor in a
+// position mode, so enabling torque does not move it
+func (c *Controller) holdPosition(id uint8) error {
+	m := c.ModelFor(id)
+	if c.goalAddrFor(id) != m.AddrGoalPosition {
+		return nil
+	}
+	c.busMu.Lock()
+	defer c.busMu.Unlock()
+	if err := c.checkPort(); err != nil {
+		return err
+	}
+	pos, err := c.driver.Read4Byte(id, m.AddrPresentPosition)
+	if err == nil {
+		err = c.driver.Write4Byte(id, m.AddrGoalPosition, pos)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to hold position: %w", err)
+	}
+	return nil
+}
+
+// waitForMotor pings a motor until it answers or the timeout expires
+func (c *Controller) waitForMotor(id uint8, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		c.busMu.Lock()
+		_, err := c.driver.Ping(id)
+		c.busMu.Unlock()
+		if err == nil || isAlertOnly(err) {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("motor %d did not come back: %w", id, err)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// StageGoals moves several motors simultaneously using Reg Write + Action.
+// Each goal is staged with Reg Write, the Registered Instruction register of
+// every motor that has one is checked, and a single broadcast Action starts
+// them together.
+// Useful on buses without Sync Write support. The control loop is paused
+// while goals are staged so no other write can slip in before the Action.
+// Goals pass the safety envelope like Send; rejected ones are not staged.
+// If staging fails, motors already staged get their current goal registered
+// instead, so a later Action does not start them.
+// Fails unless the controller is running (not faulted or e-stopped).
+func (c *Controller) StageGoals(cmds []Command) error {
+	if !c.acceptsGoals() {
+		return fmt.Errorf("cannot stage goals: controller is %v", c.State())
+	}
+	if len(cmds) == 0 {
+		return fmt.Errorf("no commands provided")
+	}
+	// groupGoals records the goals for the step limits; they only count
+	// once the Action fires them
+	ids := make([]uint8, len(cmds))
+	for i, cmd := range cmds {
+		ids[i] = cmd.ID
+	}
+	prevGoals := c.lastGoalsOf(ids)
+	groups := c.groupGoals(cmds)
+	if len(groups) == 0 {
+		return fmt.Errorf("no goals left to stage: all were rejected by the limits")
+	}
+	// A motor keeps only one registered instruction
+	regAddrs := make(map[uint8]uint16)
+	for _, g := range groups {
+		for _, id := range g.ids {
+			if _, dup := regAddrs[id]; dup {
+				c.forgetGoals(groups, prevGoals)
+				return fmt.Errorf("motor %d has more than one goal to stage", id)
+			}
+			regAddrs[id] = c.ModelFor(id).AddrRegisteredInstruction
+		}
+	}
+
+	c.busMu.Lock()
+	defer c.busMu.Unlock()
+
+	// On failure, motors already sent a Reg Write get their current goal
+	// registered instead, so a later Action (anyone's broadcast) is harmless
+	var sent []goalGroup
+	fail := func(err error) error {
+		for _, g := range sent {
+			for _, id := range g.ids {
+				data, rerr := c.driver.Read(id, g.addr, uint16(g.size))
+				if rerr == nil || isAlertOnly(rerr) {
+					rerr = c.driver.RegWrite(id, g.addr, data)
+				}
+				if rerr != nil && !isAlertOnly(rerr) {
+					fmt.Printf("Warning: motor %d may keep a staged goal: %v\n", id, rerr)
+				}
+			}
+		}
+		c.forgetGoals(groups, prevGoals)
+		return err
+	}
+
+	for _, g := range groups {
+		for _, id := range g.ids {
+			// Counted before the write: a timed-out Reg Write may still register
+			sent = append(sent, goalGroup{addr: g.addr, size: g.size, ids: []uint8{id}})
+			if err := c.driver.RegWrite(id, g.addr, encodeGoal(g.values[id], g.size)); err != nil {
+				return fail(fmt.Errorf("reg write for motor %d failed: %w", id, err))
+			}
+		}
+	}
+
+	for _, g := range groups {
+		for _, id := range g.ids {
+			if regAddrs[id] == 0 {
+				continue // Model without a Registered Instruction register
+			}
+			staged, err := c.driver.RegisteredInstruction(id, regAddrs[id])
+			if err != nil {
+				return fail(fmt.Errorf("failed to confirm staging for motor %d: %w", id, err))
+			}
+			if !staged {
+				return fail(fmt.Errorf("motor %d did not register the goal", id))
+			}
+		}
+	}
+
+	if err := c.driver.Action(BroadcastID); err != nil {
+		return fail(fmt.Errorf("action failed: %w", err))
+	}
+	return nil
+}
+
+// Stop signals the control loop to exit and waits for it to finish,
+// including the shutdown policy (SetShutdown). By default torque is disabled
+// on every motor before the port closes; ShutdownHold keeps it on.
 func (c *Controller) Stop() {
 	c.cancel()
 	c.wg.Wait()
 }
 
+// goalGroup is the set of goals written to one goal register
+type goalGroup struct {
+	addr   uint16
+	size   int     // 2 (PWM, current) or 4 bytes
+	ids    []uint8 // Command order
+	values map[uint8]uint32
+}
+
+// goalSize returns the width of a motor's goal register
+func (c *Controller) goalSize(id uint8, addr uint16) int {
+	m := c.ModelFor(id)
+	if addr == m.AddrGoalPWM || addr == m.AddrGoalCurrent {
+		return 2
+	}
+	return 4
+}
+
+// encodeGoal converts a goal value to register bytes. 2-byte registers are
+// signed, so the low 16 bits of the two's complement value are kept.
+func encodeGoal(value uint32, size int) []byte {
+	data := make([]byte, 4)
+	binary.LittleEndian.PutUint32(data, value)
+	return data[:size]
+}
+
+// loadCurrentLimits reads each motor's Current Limit so goal currents can be
+// checked before they are sent
+func (c *Controller) loadCurrentLimits(ids []uint8) {
+	for _, id := range ids {
+		def, ok := c.MotorDefinition(id)
+		if !ok {
+			continue
+		}
+		if _, ok := def.Item("Current_Limit"); !ok {
+			continue
+		}
+		limit, err := c.ReadItem(id, "Current_Limit")
+		if err != nil && !isAlertOnly(err) {
+			fmt.Printf("Warning: could not read current limit for ID %d: %v\n", id, err)
+			continue
+		}
+		c.mu.Lock()
+		if c.currentLimits == nil {
+			c.currentLimits = make(map[uint8]int64)
+		}
+		c.currentLimits[id] = limit
+		c.mu.Unlock()
+	}
+}
+
+// checkCurrent validates a raw goal current against the 16-bit register and
+// the motor's Current Limit (read at Start, or the model's range if it could
+// not be read)
+func (c *Controller) checkCurrent(id uint8, current int64) error {
+	if current > math.MaxInt16 || current < math.MinInt16 {
+		return fmt.Errorf("motor %d: goal current %d is out of range", id, current)
+	}
+	c.mu.RLock()
+	limit, ok := c.currentLimits[id]
+	def, hasDef := c.models[id]
+	c.mu.RUnlock()
+	if !ok && hasDef {
+		if it, found := def.Item("Current_Limit"); found && it.HasRange() {
+			limit, ok = it.Max, true
+		}
+	}
+	if ok && (current > limit || current < -limit) {
+		return fmt.Errorf("motor %d: goal current %d exceeds Current Limit %d", id, current, limit)
+	}
+	return nil
+}
+
+// groupGoals sorts commands by their motor's goal register, converting SI
+// goals on the way. Groups keep the order in which registers first appear.
+// Goal currents that break the Current Limit are dropped with a message, and
+// every goal passes the motor's safety envelope (SetSafetyLimits).
+func (c *Controller) groupGoals(cmds []Command) []goalGroup {
+	si := c.isSIMode()
+	var groups []goalGroup
+	index := make(map[uint32]int)
+	add := func(id uint8, addr uint16, size int, value uint32) {
+		key := uint32(addr)<<8 | uint32(size)
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, goalGroup{addr: addr, size: size, values: make(map[uint8]uint32)})
+		}
+		if _, dup := groups[i].values[id]; !dup {
+			groups[i].ids = append(groups[i].ids, id)
+		}
+		groups[i].values[id] = value
+	}
+
+	for _, cmd := range cmds {
+		m := c.ModelFor(cmd.ID)
+		addr := c.goalAddrFor(cmd.ID)
+		size := c.goalSize(cmd.ID, addr)
+		value := cmd.Value
+		current := int64(int16(value)) // What a 2-byte goal register receives
+		if si {
+			raw, err := c.siToRaw(cmd.ID, addr, cmd.SI)
+			if err != nil {
+				fmt.Printf("Goal rejected: %v\n", err)
+				continue
+			}
+			value = raw
+			current = int64(int32(raw))
+		} else if size == 2 && value > math.MaxUint16 {
+			// Raw 2-byte goals are given as 16-bit or sign-extended 32-bit
+			// two's complement; anything else would be truncated
+			if err := checkGoalRange(cmd.ID, int64(int32(value)), size); err != nil {
+				fmt.Printf("Goal rejected: %v\n", err)
+				continue
+			}
+		}
+		if addr == m.AddrGoalCurrent {
+			if err := c.checkCurrent(cmd.ID, current); err != nil {
+				fmt.Printf("Goal rejected: %v\n", err)
+				continue
+			}
+		}
+		value, ok := c.enforceLimits(cmd.ID, addr, size, value)
+		if !ok {
+			continue
+		}
+		add(cmd.ID, addr, size, value)
+
+		if cmd.WithCurrent && addr != m.AddrGoalCurrent {
+			if m.AddrGoalCurrent == 0 {
+				fmt.Printf("Goal current ignored: motor %d has no Goal Current register\n", cmd.ID)
+				continue
+			}
+			current := int64(cmd.Current)
+			if si {
+				current = c.UnitsFor(cmd.ID).AmpsToCurrent(cmd.CurrentSI)
+			}
+			if err := c.checkCurrent(cmd.ID, current); err != nil {
+				fmt.Printf("Goal current rejected: %v\n", err)
+				continue
+			}
+			limited, ok := c.enforceLimits(cmd.ID, m.AddrGoalCurrent, 2, uint32(uint16(current)))
+			if !ok {
+				continue
+			}
+			add(cmd.ID, m.AddrGoalCurrent, 2, limited)
+		}
+	}
+	return groups
+}
+
+// writeGoals sends each command to its motor's goal register(s). Motors
+// sharing a register go out in one Sync Write. When motors use different
+// registers (e.g. position joints and a current-controlled gripper) all
+// goals go out in a single Bulk Write, or one Sync Write per register if the
+// protocol has no Bulk Write or a motor has two goals (position + current).
+func (c *Controller) writeGoals(cmds []Command) {
+	c.writeGroups(c.groupGoals(cmds))
+}
+
+// writeGroups sends goals already checked by groupGoals (see writeGoals)
+func (c *Controller) writeGroups(groups []goalGroup) {
+	if len(groups) == 0 {
+		return
+	}
+
+	c.busMu.Lock()
+	defer c.busMu.Unlock()
+
+	if !c.isSyncMode() {
+		// Individual writes for single motor or legacy mode
+		for _, g := range groups {
+			for _, id := range g.ids {
+				if err := c.driver.Write(id, g.addr, encodeGoal(g.values[id], g.size)); err != nil {
+					fmt.Printf("Write error for motor %d: %v\n", id, err)
+				}
+			}
+		}
+		return
+	}
+
+	if len(groups) > 1 && c.driver.Protocol().supports(InstBulkWrite) {
+		var entries []BulkWriteData
+		seen := make(map[uint8]bool)
+		unique := true
+		for _, g := range groups {
+			for _, id := range g.ids {
+				if seen[id] {
+					unique = false
+				}
+				seen[id] = true
+				entries = append(entries, BulkWriteData{ID: id, Addr: g.addr, Data: encodeGoal(g.values[id], g.size)})
+			}
+		}
+		// Bulk Write takes one entry per motor
+		if unique {
+			if err := c.driver.BulkWrite(entries); err != nil {
+				fmt.Printf("BulkWrite error: %v\n", err)
+			}
+			return
+		}
+	}
+
+	// Use Sync Write for multiple motors (more efficient)
+	for _, g := range groups {
+		motors := make([]SyncWriteData, 0, len(g.ids))
+		for _, id := range g.ids {
+			motors = append(motors, SyncWriteData{ID: id, Data: encodeGoal(g.values[id], g.size)})
+		}
+		if err := c.driver.SyncWrite(g.addr, uint16(g.size), motors); err != nil {
+			fmt.Printf("SyncWrite error: %v\n", err)
+		}
+	}
+}
+
 func (c *Controller) controlLoop() {
 	defer c.wg.Done()
 
 	// 1. Lock OS Thread to reduce scheduler jitter
 	runtime.LockOSThread()
 	defer runtime.UnlockOSThread()
-	defer c.driver.port.Close()
+	defer c.shutdown() // Shutdown policy, then close the port
 
+	var lastStart, deadline time.Time
 	for {
 		select {
 		case <-c.ctx.Done():
 			return
-		// 1. Process Commands (Prioritized)
-		case cmds := <-c.CommandChan:
-			goalAddr := c.getActiveGoalAddr()
-			if c.isSyncMode() {
-				// Use Sync Write for multiple motors (more efficient)
-				values := make(map[uint8]uint32)
-				for _, cmd := range cmds {
-					values[cmd.ID] = cmd.Value
-				}
-				if err := c.driver.SyncWrite4Byte(goalAddr, values); err != nil {
-					fmt.Printf("SyncWrite error: %v\n", err)
-				}
-			} else {
-				// Individual writes for single motor or legacy mode
-				for _, cmd := range cmds {
-					if err := c.driver.Write4Byte(cmd.ID, goalAddr, cmd.Value); err != nil {
-						fmt.Printf("Write error for motor %d: %v\n", cmd.ID, err)
-					}
-				}
-			}
 		default:
-			// No commands, continue to reads
 		}
 
-		// 2. Read Feedback
-		var feedbacks []Feedback
-		motorIDs := c.getMotorIDs()
-
-		if c.isSyncMode() {
-			// Use Sync Read for multiple motors (more efficient)
-			values, err := c.driver.SyncRead4Byte(c.Model.AddrPresentPosition, motorIDs)
-			if err != nil {
-				// Error reading all motors, create error feedback for each
-				for _, id := range motorIDs {
-					feedbacks = append(feedbacks, Feedback{ID: id, Value: 0, Error: err})
-				}
-			} else {
-				// Success, create feedback for each motor
-				for _, id := range motorIDs {
-					if val, ok := values[id]; ok {
-						feedbacks = append(feedbacks, Feedback{ID: id, Value: val, Error: nil})
-					} else {
-						feedbacks = append(feedbacks, Feedback{ID: id, Value: 0, Error: fmt.Errorf("no data for motor %d", id)})
-					}
-				}
-			}
-		} else {
-			// Individual reads for single motor
-			for _, id := range motorIDs {
-				val, err := c.driver.Read4Byte(id, c.Model.AddrPresentPosition)
-				feedbacks = append(feedbacks, Feedback{ID: id, Value: val, Error: err})
-			}
+		start := time.Now()
+		if !lastStart.IsZero() {
+			c.stats.recordPeriod(start.Sub(lastStart))
 		}
+		lastStart = start
 
-		// Send feedback (non-blocking)
-		select {
-		case c.FeedbackChan <- feedbacks:
-		default:
-			// Channel full, drop oldest feedback
+		c.runCycle()
+
+		period := c.getPeriod()
+		if period == 0 {
+			// Free-running: next cycle immediately
+			deadline = time.Time{}
+			continue
+		}
+		if deadline.IsZero() {
+			deadline = start
+		}
+		var missed uint64
+		deadline, missed = nextDeadline(deadline, time.Now(), period)
+		if missed > 0 {
+			c.stats.recordOverrun(missed)
+		}
+		if !sleepUntil(c.ctx, deadline) {
+			return
 		}
 	}
 }
+
+// runCycle runs one control cycle: write pending goals, read feedback and
+// publish it, timing the bus phases
+func (c *Controller) runCycle() {
+	var writeTime time.Duration
+	wrote := false
+
+	// 1. Process Commands (Prioritized), newest goal per motor.
+	// Faulted or e-stopped: goals are drained and dropped.
+	t := time.Now()
+	if cmds := c.drainCommands(t); len(cmds) > 0 && c.acceptsGoals() {
+		c.writeGoals(cmds)
+		writeTime, wrote = time.Since(t), true
+	}
+
+	// 2. Read Feedback (offline motors are skipped and probed instead)
+	ids := c.getMotorIDs()
+	t = time.Now()
+	feedbacks := c.withOffline(ids, c.readFeedback(c.onlineIDs(ids)))
+	readTime := time.Since(t)
+	c.stats.recordCycle(writeTime, readTime, wrote)
+	c.checkFaults(feedbacks)
+
+	// 3. Offline probes and bus watchdog
+	var gap time.Duration
+	if !c.lastRead.IsZero() {
+		gap = t.Sub(c.lastRead)
+	}
+	c.lastRead = t.Add(readTime)
+	c.serviceLinks(c.lastRead, gap)
+
+	if c.isSIMode() {
+		c.fillSI(feedbacks)
+	}
+
+	// Publish feedback (non-blocking)
+	c.publishFeedback(feedbacks)
+}
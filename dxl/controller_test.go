@@ -0,0 +1,533 @@
+package dxl
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+// newTestController creates a controller bound to the given port without
+// opening a serial device or starting the control loop.
+func newTestController(port SerialPortInterface, ids []uint8) *Controller {
+	c := NewController("test", 1000000, ModelXSeries)
+	c.driver = NewDriver(port)
+	c.SetMotorIDs(ids)
+	return c
+}
+
+func TestControllerStageGoals(t *testing.T) {
+	mock := NewScriptedSerialPort(
+		buildStatusPacket(1, 0, nil),       // Reg Write ID 1
+		buildStatusPacket(2, 0, nil),       // Reg Write ID 2
+		buildStatusPacket(1, 0, []byte{1}), // Registered Instruction ID 1
+		buildStatusPacket(2, 0, []byte{1}), // Registered Instruction ID 2
+	)
+	ctrl := runningController(mock, []uint8{1, 2})
+
+	err := ctrl.StageGoals([]Command{{ID: 1, Value: 1024}, {ID: 2, Value: 3072}})
+	if err != nil {
+		t.Fatalf("StageGoals failed: %v", err)
+	}
+
+	// Last packet must be a broadcast Action: FF FF FD 00 FE 03 00 05 CRC CRC
+	written := mock.GetWritten()
+	action := written[len(written)-10:]
+	if action[4] != BroadcastID || action[7] != InstAction {
+		t.Errorf("Expected broadcast Action last, got %X", action)
+	}
+}
+
+func TestControllerStageGoalsNotRegistered(t *testing.T) {
+	mock := NewScriptedSerialPort(
+		buildStatusPacket(1, 0, nil),
+		buildStatusPacket(1, 0, []byte{0}),                      // Nothing staged
+		buildStatusPacket(1, 0, []byte{0x00, 0x02, 0x00, 0x00}), // Goal Position
+		buildStatusPacket(1, 0, nil),                            // Reg Write of it
+	)
+	ctrl := runningController(mock, []uint8{1})
+
+	if err := ctrl.StageGoals([]Command{{ID: 1, Value: 1024}}); err == nil {
+		t.Error("Expected error when the motor did not register the goal")
+	}
+}
+
+func TestControllerStageGoalsNoRegisteredInstruction(t *testing.T) {
+	mock := NewScriptedSerialPort(buildStatusPacket(1, 0, nil)) // Reg Write
+	ctrl := runningController(mock, []uint8{1})
+	ctrl.Model.AddrRegisteredInstruction = 0 // e.g. a .model file without the item
+
+	if err := ctrl.StageGoals([]Command{{ID: 1, Value: 1024}}); err != nil {
+		t.Fatalf("StageGoals failed: %v", err)
+	}
+	p := ctrl.driver.Protocol()
+	want := append(p.BuildPacket(1, InstRegWrite, []byte{116, 0, 0x00, 0x04, 0, 0}), p.BuildPacket(BroadcastID, InstAction, nil)...)
+	if !bytes.Equal(mock.GetWritten(), want) {
+		t.Errorf("written %X, want Reg Write then Action", mock.GetWritten())
+	}
+}
+
+func TestControllerStageGoalsFailureClearsStaged(t *testing.T) {
+	mock := NewScriptedSerialPort(
+		buildStatusPacket(1, 0, nil),                            // Reg Write ID 1
+		buildStatusPacket(2, 0x07, nil),                         // Reg Write ID 2: access error
+		buildStatusPacket(1, 0, []byte{0x00, 0x02, 0x00, 0x00}), // Goal Position ID 1
+		buildStatusPacket(1, 0, nil),                            // Reg Write ID 1 of it
+		buildStatusPacket(2, 0, []byte{0x00, 0x06, 0x00, 0x00}), // Goal Position ID 2
+		buildStatusPacket(2, 0, nil),                            // Reg Write ID 2 of it
+	)
+	ctrl := runningController(mock, []uint8{1, 2})
+	ctrl.lastGoals = map[uint8]map[uint16]int64{1: {116: 512}}
+
+	if err := ctrl.StageGoals([]Command{{ID: 1, Value: 1024}, {ID: 2, Value: 3072}}); err == nil {
+		t.Fatal("Expected error when a Reg Write fails")
+	}
+	p := ctrl.driver.Protocol()
+	written := mock.GetWritten()
+	clear1 := p.BuildPacket(1, InstRegWrite, []byte{116, 0, 0x00, 0x02, 0, 0})
+	clear2 := p.BuildPacket(2, InstRegWrite, []byte{116, 0, 0x00, 0x06, 0, 0})
+	if !bytes.Contains(written, clear1) || !bytes.Contains(written, clear2) {
+		t.Errorf("written %X, want the present goals registered again", written)
+	}
+	if bytes.Contains(written, p.BuildPacket(BroadcastID, InstAction, nil)) {
+		t.Error("Action must not be sent after a failed Reg Write")
+	}
+	if got := ctrl.lastGoals[1][116]; got != 512 {
+		t.Errorf("last goal of motor 1 = %d, want 512 restored", got)
+	}
+	if _, ok := ctrl.lastGoals[2][116]; ok {
+		t.Error("motor 2 should have no last goal recorded")
+	}
+}
+
+func TestControllerStageGoalsNotRunning(t *testing.T) {
+	mock := NewScriptedSerialPort()
+	ctrl := runningController(mock, []uint8{1})
+	ctrl.setState(StateEStopped, nil)
+
+	if err := ctrl.StageGoals([]Command{{ID: 1, Value: 1024}}); err == nil {
+		t.Error("Expected error when staging goals on an e-stopped controller")
+	}
+	if len(mock.GetWritten()) != 0 {
+		t.Errorf("Nothing should be sent, got %X", mock.GetWritten())
+	}
+}
+
+func TestControllerRecoverMotor(t *testing.T) {
+	mock := NewScriptedSerialPort(
+		buildStatusPacket(1, alertBit, []byte{byte(HwErrOverload)}), // Hardware Error Status
+		buildStatusPacket(1, alertBit, nil),                         // Reboot
+		buildStatusPacket(1, 0, []byte{0x24, 0x04, 0x2A}),           // Ping
+		buildStatusPacket(1, 0, []byte{OpModePosition}),             // Operating Mode
+		buildStatusPacket(1, 0, []byte{0x00, 0x08, 0x00, 0x00}),     // Present Position
+		buildStatusPacket(1, 0, nil),                                // Goal Position
+		buildStatusPacket(1, 0, nil),                                // Torque Enable
+		buildStatusPacket(1, 0, []byte{1}),                          // Torque Enable readback
+	)
+	ctrl := newTestController(mock, []uint8{1})
+	ctrl.opModes[1] = OpModePosition
+
+	hwErr, err := ctrl.RecoverMotor(1)
+	if err != nil {
+		t.Fatalf("RecoverMotor failed: %v", err)
+	}
+	if hwErr != HwErrOverload {
+		t.Errorf("Cleared error: got %v, want %v", hwErr, HwErrOverload)
+	}
+}
+
+func TestControllerRecoverMotorNoError(t *testing.T) {
+	mock := NewScriptedSerialPort(buildStatusPacket(1, 0, []byte{0}))
+	ctrl := newTestController(mock, []uint8{1})
+
+	hwErr, err := ctrl.RecoverMotor(1)
+	if err != nil || hwErr != 0 {
+		t.Errorf("Expected no-op, got %v, %v", hwErr, err)
+	}
+}
+
+func TestControllerConfigureModelsAuto(t *testing.T) {
+	c := NewController("test", 1000000, MotorModel{})
+	if err := c.configureModels([]uint8{1, 2}, map[uint8]uint16{1: 1020, 2: 1060}); err != nil {
+		t.Fatalf("configureModels failed: %v", err)
+	}
+	if c.Model != ModelXSeries {
+		t.Errorf("Model: got %+v, want X-series", c.Model)
+	}
+	if c.goalAddrFor(1) != ModelXSeries.AddrGoalPosition {
+		t.Errorf("Goal address not updated: %d", c.goalAddrFor(1))
+	}
+	if def, ok := c.MotorDefinition(2); !ok || def.Name != "XL430-W250" {
+		t.Errorf("MotorDefinition(2): got %v, %v", def, ok)
+	}
+}
+
+func TestControllerConfigureModelsMixed(t *testing.T) {
+	// XM430 and H54 on one bus: each gets its own layout
+	c := NewController("test", 1000000, MotorModel{})
+	if err := c.configureModels([]uint8{1, 2}, map[uint8]uint16{1: 1020, 2: 54024}); err != nil {
+		t.Fatalf("configureModels failed: %v", err)
+	}
+	if c.ModelFor(1) != ModelXSeries || c.ModelFor(2) != ModelProSeries {
+		t.Errorf("per-motor models: 1=%+v 2=%+v", c.ModelFor(1), c.ModelFor(2))
+	}
+	if c.goalAddrFor(2) != ModelProSeries.AddrGoalPosition {
+		t.Errorf("motor 2 goal address: got %d", c.goalAddrFor(2))
+	}
+}
+
+func TestControllerConfigureModelsMismatch(t *testing.T) {
+	// Configured addresses must match the detected model
+	c := NewController("test", 1000000, ModelProSeries)
+	if err := c.configureModels([]uint8{1}, map[uint8]uint16{1: 1020}); err == nil {
+		t.Error("expected error for configured model mismatch")
+	}
+
+	// Per-motor assignment is checked too
+	c = NewController("test", 1000000, ModelXSeries)
+	c.SetMotorModel(2, ModelXSeries)
+	if err := c.configureModels([]uint8{1, 2}, map[uint8]uint16{1: 1020, 2: 54024}); err == nil {
+		t.Error("expected error for per-motor model mismatch")
+	}
+
+	// Correct per-motor assignment makes a mixed bus with a configured Model work
+	c = NewController("test", 1000000, ModelXSeries)
+	c.SetMotorModel(2, ModelProSeries)
+	if err := c.configureModels([]uint8{1, 2}, map[uint8]uint16{1: 1020, 2: 54024}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestControllerConfigureModelsUnknown(t *testing.T) {
+	c := NewController("test", 1000000, MotorModel{})
+	if err := c.configureModels([]uint8{1}, map[uint8]uint16{1: 9999}); err == nil {
+		t.Error("expected error for unknown model without a configured model")
+	}
+
+	// A configured model is trusted for unknown motors
+	c = NewController("test", 1000000, ModelXSeries)
+	if err := c.configureModels([]uint8{1}, map[uint8]uint16{1: 9999}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestControllerSIConversion(t *testing.T) {
+	c := NewController("test", 1000000, MotorModel{})
+	if err := c.configureModels([]uint8{1}, map[uint8]uint16{1: 1020}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Position: 0 rad is the center tick
+	if got, err := c.siToRaw(1, c.Model.AddrGoalPosition, 0); err != nil || got != 2048 {
+		t.Errorf("position goal: got %d (%v), want 2048", got, err)
+	}
+	// Velocity: negative goals become two's complement
+	want := uint32(int32(XSeriesUnits.RadPerSecToVelocity(-1)))
+	if got, err := c.siToRaw(1, c.Model.AddrGoalVelocity, -1); err != nil || got != want {
+		t.Errorf("velocity goal: got %d (%v), want %d", got, err, want)
+	}
+	// Goals that would wrap in their register are rejected
+	if _, err := c.siToRaw(1, c.Model.AddrGoalPWM, -1e4); err == nil {
+		t.Error("PWM goal beyond the 2-byte range should be rejected")
+	}
+	if _, err := c.siToRaw(1, c.Model.AddrGoalPosition, 1e7); err == nil {
+		t.Error("position goal beyond the 4-byte range should be rejected")
+	}
+
+	fb := []Feedback{{ID: 1, State: MotorState{Fields: FieldPosition, Position: 2048 + 1024}}}
+	c.fillSI(fb)
+	if math.Abs(fb[0].SI-math.Pi/2) > 1e-3 {
+		t.Errorf("feedback SI: got %v, want pi/2", fb[0].SI)
+	}
+}
+
+func TestControllerSIStateFields(t *testing.T) {
+	c := NewController("test", 1000000, MotorModel{})
+	if err := c.configureModels([]uint8{1, 2}, map[uint8]uint16{1: 1020, 2: 1060}); err != nil {
+		t.Fatal(err)
+	}
+	state := MotorState{
+		Fields:       FieldPosition | FieldVelocity | FieldCurrent | FieldPWM | FieldInputVoltage | FieldTemperature,
+		Position:     2048,
+		Velocity:     100,
+		Current:      -200,
+		PWM:          442,
+		InputVoltage: 120,
+		Temperature:  40,
+	}
+	fb := []Feedback{{ID: 1, State: state}, {ID: 2, State: state}}
+	c.fillSI(fb)
+
+	u := c.UnitsFor(1)
+	got := fb[0].StateSI
+	want := MotorStateSI{
+		Position:    0,
+		Velocity:    u.VelocityToRadPerSec(100),
+		Current:     u.CurrentToAmps(-200),
+		PWM:         u.PWMToPercent(442),
+		Voltage:     12,
+		Temperature: 40,
+	}
+	if got != want {
+		t.Errorf("StateSI = %+v, want %+v", got, want)
+	}
+	if got.Current >= 0 || got.Velocity <= 0 {
+		t.Errorf("signs lost: %+v", got)
+	}
+	// XL430 reports Present Load, which has no SI current
+	if fb[1].StateSI.Current != 0 || fb[1].StateSI.Velocity == 0 {
+		t.Errorf("XL430 StateSI = %+v", fb[1].StateSI)
+	}
+}
+
+func TestControllerReadFeedbackMixedBulk(t *testing.T) {
+	mock := NewScriptedSerialPort(append(
+		buildStatusPacket(1, 0, []byte{0x00, 0x08, 0x00, 0x00}), // XM430: 2048
+		buildStatusPacket(2, 0, []byte{0x10, 0x00, 0x00, 0x00})...,
+	))
+	ctrl := newTestController(mock, []uint8{1, 2})
+	ctrl.Model = MotorModel{} // auto-detect
+	if err := ctrl.configureModels([]uint8{1, 2}, map[uint8]uint16{1: 1020, 2: 54024}); err != nil {
+		t.Fatal(err)
+	}
+
+	fbs := ctrl.readFeedback([]uint8{1, 2})
+	written := mock.GetWritten()
+	if written[7] != InstBulkRead {
+		t.Fatalf("Expected Bulk Read for mismatched layouts, got %X", written)
+	}
+	// ID 1 at 132, ID 2 at 611 (0x263)
+	if !bytes.Contains(written, []byte{1, 132, 0, 4, 0, 2, 0x63, 0x02, 4, 0}) {
+		t.Errorf("unexpected bulk read params %X", written)
+	}
+	if fbs[0].State.Position != 2048 || fbs[1].State.Position != 16 {
+		t.Errorf("feedback: %+v", fbs)
+	}
+}
+
+func TestControllerPerMotorModelWrites(t *testing.T) {
+	mock := NewScriptedSerialPort()
+	ctrl := newTestController(mock, []uint8{1, 2})
+	ctrl.SetMotorModel(2, ModelProPlusSeries)
+
+	ctrl.writeGoals([]Command{{ID: 1, Value: 1}, {ID: 2, Value: 2}})
+	// Different goal registers (116 vs 564 = 0x234): one Bulk Write
+	written := mock.GetWritten()
+	if written[7] != InstBulkWrite || !bytes.Contains(written, []byte{2, 0x34, 0x02, 4, 0}) {
+		t.Errorf("unexpected write %X", written)
+	}
+}
+
+func TestControllerPerMotorGoalAddr(t *testing.T) {
+	ctrl := newTestController(NewScriptedSerialPort(), []uint8{1, 2, 3})
+	if err := ctrl.setMode(3, OpModeVelocity); err != nil {
+		t.Fatal(err)
+	}
+
+	// Changing motor 3 must not affect motors 1 and 2
+	if ctrl.goalAddrFor(1) != ModelXSeries.AddrGoalPosition || ctrl.goalAddrFor(3) != ModelXSeries.AddrGoalVelocity {
+		t.Errorf("goal addresses: 1=%d 3=%d", ctrl.goalAddrFor(1), ctrl.goalAddrFor(3))
+	}
+
+	groups := ctrl.groupGoals([]Command{{ID: 1, Value: 100}, {ID: 3, Value: 50}, {ID: 2, Value: 200}})
+	if len(groups) != 2 {
+		t.Fatalf("Expected 2 groups, got %d", len(groups))
+	}
+	if groups[0].addr != 116 || len(groups[0].ids) != 2 || groups[1].addr != 104 || groups[1].values[3] != 50 {
+		t.Errorf("groups: %+v", groups)
+	}
+}
+
+func TestControllerWriteGoalsBulk(t *testing.T) {
+	mock := NewScriptedSerialPort()
+	ctrl := newTestController(mock, []uint8{1, 2})
+	ctrl.setMode(2, OpModeVelocity)
+
+	ctrl.writeGoals([]Command{{ID: 1, Value: 2048}, {ID: 2, Value: 10}})
+
+	written := mock.GetWritten()
+	if written[7] != InstBulkWrite {
+		t.Fatalf("Expected one Bulk Write, got %X", written)
+	}
+	// ID 1 -> 116, ID 2 -> 104
+	if !bytes.Contains(written, []byte{1, 116, 0, 4, 0, 0x00, 0x08, 0, 0, 2, 104, 0, 4, 0, 10, 0, 0, 0}) {
+		t.Errorf("unexpected bulk params %X", written)
+	}
+}
+
+func TestControllerWriteGoalsSyncPerRegister(t *testing.T) {
+	// Protocol 1.0 has no Bulk Write: one Sync Write per register
+	mock := NewScriptedSerialPort()
+	ctrl := newTestController(mock, []uint8{1, 2})
+	ctrl.driver = NewDriverWithProtocol(mock, Protocol1)
+	ctrl.setMode(2, OpModeVelocity)
+
+	ctrl.writeGoals([]Command{{ID: 1, Value: 2048}, {ID: 2, Value: 10}})
+
+	written := mock.GetWritten()
+	if n := bytes.Count(written, []byte{0xFF, 0xFF, BroadcastID}); n != 2 {
+		t.Errorf("Expected 2 sync writes, got %d: %X", n, written)
+	}
+}
+
+func TestControllerSetOperatingModeUnsupported(t *testing.T) {
+	// Legacy PRO has no Goal PWM: rejected before touching the bus
+	mock := NewScriptedSerialPort()
+	ctrl := NewController("test", 1000000, ModelProSeries)
+	ctrl.driver = NewDriver(mock)
+	if err := ctrl.SetOperatingMode(1, OpModePWM); err == nil {
+		t.Error("Expected error for PWM mode without Goal PWM")
+	}
+	if len(mock.GetWritten()) != 0 {
+		t.Errorf("Nothing should be sent, got %X", mock.GetWritten())
+	}
+}
+
+func TestControllerLoadOperatingModes(t *testing.T) {
+	mock := NewScriptedSerialPort(
+		buildStatusPacket(1, 0, []byte{OpModePosition}),
+		buildStatusPacket(2, 0, []byte{OpModePWM}),
+	)
+	ctrl := newTestController(mock, []uint8{1, 2})
+	ctrl.loadOperatingModes([]uint8{1, 2})
+
+	if mode, ok := ctrl.OperatingMode(2); !ok || mode != OpModePWM {
+		t.Errorf("OperatingMode(2): got %d, %v", mode, ok)
+	}
+	if ctrl.goalAddrFor(2) != ModelXSeries.AddrGoalPWM || ctrl.goalAddrFor(1) != ModelXSeries.AddrGoalPosition {
+		t.Errorf("goal addresses: 1=%d 2=%d", ctrl.goalAddrFor(1), ctrl.goalAddrFor(2))
+	}
+}
+
+func TestControllerPWMGoalRange(t *testing.T) {
+	mock := NewScriptedSerialPort()
+	ctrl := newTestController(mock, []uint8{1, 2, 3})
+	for _, id := range []uint8{1, 2, 3} {
+		ctrl.setMode(id, OpModePWM)
+	}
+
+	groups := ctrl.groupGoals([]Command{
+		{ID: 1, Value: uint32(0xFFFFFF9C)}, // -100, sign-extended
+		{ID: 2, Value: 0x10064},            // Would truncate to 100
+		{ID: 3, Value: uint32(0xFFFF7FFF)}, // Below the int16 range
+	})
+	if len(groups) != 1 || len(groups[0].ids) != 1 || groups[0].ids[0] != 1 {
+		t.Fatalf("groups = %+v, want only motor 1", groups)
+	}
+	if got := decodeGoal(groups[0].values[1], 2); got != -100 {
+		t.Errorf("motor 1 goal = %d, want -100", got)
+	}
+}
+
+func TestControllerCurrentModeGoal(t *testing.T) {
+	mock := NewScriptedSerialPort()
+	ctrl := newTestController(mock, []uint8{1, 2})
+	ctrl.setMode(1, OpModeCurrent)
+	ctrl.setMode(2, OpModeCurrent)
+
+	if ctrl.goalAddrFor(1) != 102 {
+		t.Fatalf("current mode goal address: got %d, want 102", ctrl.goalAddrFor(1))
+	}
+
+	neg := int16(-100)
+	ctrl.writeGoals([]Command{{ID: 1, Value: uint32(uint16(neg))}, {ID: 2, Value: 50}})
+
+	// Sync Write of 2 bytes at 102: [102, 0, 2, 0, ID1, 0x9C, 0xFF, ID2, 50, 0]
+	want := []byte{102, 0, 2, 0, 1, 0x9C, 0xFF, 2, 50, 0}
+	if !bytes.Contains(mock.GetWritten(), want) {
+		t.Errorf("expected %X in %X", want, mock.GetWritten())
+	}
+}
+
+func TestControllerCurrentBasedPosition(t *testing.T) {
+	mock := NewScriptedSerialPort()
+	ctrl := newTestController(mock, []uint8{1, 2})
+	ctrl.setMode(1, OpModeCurrentBasedPos)
+	ctrl.setMode(2, OpModeCurrentBasedPos)
+
+	ctrl.writeGoals([]Command{
+		{ID: 1, Value: 2048, WithCurrent: true, Current: 300},
+		{ID: 2, Value: 1024, WithCurrent: true, Current: 200},
+	})
+
+	// Each motor has two goals, so two Sync Writes instead of a Bulk Write
+	written := mock.GetWritten()
+	if !bytes.Contains(written, []byte{116, 0, 4, 0, 1, 0x00, 0x08, 0, 0}) {
+		t.Errorf("goal position sync write missing: %X", written)
+	}
+	if !bytes.Contains(written, []byte{102, 0, 2, 0, 1, 0x2C, 0x01, 2, 200, 0}) {
+		t.Errorf("goal current sync write missing: %X", written)
+	}
+}
+
+func TestControllerCurrentLimit(t *testing.T) {
+	mock := NewScriptedSerialPort()
+	ctrl := newTestController(mock, []uint8{1})
+	ctrl.setMode(1, OpModeCurrent)
+	ctrl.currentLimits = map[uint8]int64{1: 500}
+
+	if err := ctrl.checkCurrent(1, -501); err == nil {
+		t.Error("Expected error beyond Current Limit")
+	}
+	ctrl.writeGoals([]Command{{ID: 1, Value: 600}})
+	if len(mock.GetWritten()) != 0 {
+		t.Errorf("Out-of-limit goal must not be sent, got %X", mock.GetWritten())
+	}
+
+	// Without a register reading, the model's range applies
+	ctrl = newTestController(mock, []uint8{1})
+	ctrl.Model = MotorModel{}
+	ctrl.configureModels([]uint8{1}, map[uint8]uint16{1: 1020})
+	if err := ctrl.checkCurrent(1, 1194); err == nil {
+		t.Error("Expected error beyond XM430 current range")
+	}
+}
+
+func TestControllerCurrentSIOverflow(t *testing.T) {
+	mock := NewScriptedSerialPort()
+	ctrl := newTestController(mock, []uint8{1, 2})
+	ctrl.setMode(2, OpModeCurrent)
+	ctrl.currentLimits = map[uint8]int64{1: 500, 2: 500}
+	ctrl.SetSIMode(true)
+
+	// 65536 + 100 units would wrap to 100 in 16 bits, inside the limit
+	amps := XSeriesUnits.CurrentToAmps(65536 + 100)
+	if err := ctrl.checkCurrent(1, 65536+100); err == nil {
+		t.Error("Expected error beyond the 16-bit range")
+	}
+	groups := ctrl.groupGoals([]Command{
+		{ID: 1, SI: 0, WithCurrent: true, CurrentSI: amps},
+		{ID: 2, SI: amps},
+	})
+	for _, g := range groups {
+		if g.addr == 102 {
+			t.Errorf("wrapped goal current was kept: %+v", g)
+		}
+	}
+}
+
+func TestControllerCurrentGripperBulk(t *testing.T) {
+	// Position joint + current-controlled gripper: one Bulk Write, 2-byte gripper entry
+	mock := NewScriptedSerialPort()
+	ctrl := newTestController(mock, []uint8{1, 2})
+	ctrl.setMode(2, OpModeCurrent)
+	ctrl.SetSIMode(true)
+
+	ctrl.writeGoals([]Command{{ID: 1, SI: 0}, {ID: 2, SI: 0.269}})
+
+	written := mock.GetWritten()
+	if written[7] != InstBulkWrite {
+		t.Fatalf("Expected Bulk Write, got %X", written)
+	}
+	// 0.269 A / 2.69 mA = 100
+	if !bytes.Contains(written, []byte{2, 102, 0, 2, 0, 100, 0}) {
+		t.Errorf("gripper entry missing: %X", written)
+	}
+}
+
+func TestControllerCurrentModeUnsupported(t *testing.T) {
+	ctrl := newTestController(NewScriptedSerialPort(), []uint8{1})
+	ctrl.SetMotorModel(1, ModelProSeries)
+	if err := ctrl.SetOperatingMode(1, OpModeCurrent); err == nil {
+		t.Error("Expected error: legacy PRO has no Goal Current")
+	}
+}
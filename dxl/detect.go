@@ -0,0 +1,124 @@
+package dxl
+
+import (
+	"context"
+	"fmt"
+)
+
+// StandardBaudRates are the rates selectable through the X-series Baud Rate
+// register (values 0-7). Rates the host serial driver cannot produce are
+// skipped during detection.
+var StandardBaudRates = []int{9600, 57600, 115200, 1000000, 2000000, 3000000, 4000000, 4500000}
+
+// ReconfigurablePort is a serial port whose baud rate can be changed without
+// reopening it. *SerialPort implements it on every platform.
+type ReconfigurablePort interface {
+	SerialPortInterface
+	SetBaudRate(baud int) error
+}
+
+// DetectedDevice is a motor that answered during auto-detection, with the
+// bus settings it answered at.
+type DetectedDevice struct {
+	DeviceInfo
+	BaudRate int
+	Protocol int // 1 or 2
+}
+
+// DetectOptions limits what DetectDevices tries. Zero values select defaults.
+type DetectOptions struct {
+	BaudRates []int      // Default: StandardBaudRates
+	Protocols []Protocol // Default: Protocol2, Protocol1
+	MaxID     uint8      // Highest ID probed by sequential (Protocol 1.0) scans. Default: MaxID
+}
+
+// AutoDetect opens the named port and searches every standard baud rate and
+// both protocol versions for motors. See DetectDevices.
+func AutoDetect(ctx context.Context, portName string, opts DetectOptions) ([]DetectedDevice, error) {
+	bauds := opts.BaudRates
+	if len(bauds) == 0 {
+		bauds = StandardBaudRates
+	}
+
+	// Open at any rate the host supports; DetectDevices reconfigures it
+	var sp *SerialPort
+	var err error
+	for _, baud := range bauds {
+		if sp, err = OpenSerial(portName, baud); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open serial port: %v", err)
+	}
+	defer sp.Close()
+
+	return DetectDevices(ctx, sp, opts)
+}
+
+// DetectDevices reconfigures an open port to each baud rate and protocol in
+// turn and scans the bus, reporting every device that answers and the
+// settings it answered at. The port is left at the last rate tried.
+func DetectDevices(ctx context.Context, port ReconfigurablePort, opts DetectOptions) ([]DetectedDevice, error) {
+	bauds := opts.BaudRates
+	if len(bauds) == 0 {
+		bauds = StandardBaudRates
+	}
+	protocols := opts.Protocols
+	if len(protocols) == 0 {
+		protocols = []Protocol{Protocol2, Protocol1}
+	}
+	maxID := opts.MaxID
+	if maxID == 0 || maxID > MaxID {
+		maxID = MaxID
+	}
+
+	var detected []DetectedDevice
+	var lastErr error
+	usable := 0
+
+	for _, baud := range bauds {
+		if err := port.SetBaudRate(baud); err != nil {
+			lastErr = fmt.Errorf("baud %d: %v", baud, err)
+			continue
+		}
+		usable++
+
+		for _, proto := range protocols {
+			if err := ctx.Err(); err != nil {
+				return detected, err
+			}
+
+			driver := NewDriverWithProtocol(port, proto)
+			driver.BaudRate = baud
+
+			var devices []DeviceInfo
+			var err error
+			if proto.Version() == 1 {
+				devices, err = driver.ScanRange(ctx, 0, maxID)
+			} else {
+				devices, err = driver.Scan(ctx)
+			}
+			if err != nil {
+				if ctx.Err() != nil {
+					return detected, ctx.Err()
+				}
+				lastErr = fmt.Errorf("baud %d, protocol %d.0: %v", baud, proto.Version(), err)
+				continue
+			}
+
+			for _, dev := range devices {
+				detected = append(detected, DetectedDevice{
+					DeviceInfo: dev,
+					BaudRate:   baud,
+					Protocol:   proto.Version(),
+				})
+			}
+		}
+	}
+
+	if usable == 0 {
+		return nil, fmt.Errorf("no usable baud rate: %v", lastErr)
+	}
+	return detected, nil
+}
@@ -0,0 +1,78 @@
+package dxl
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// baudSimPort simulates a single Protocol 2.0 motor that only understands
+// packets sent at its configured baud rate.
+type baudSimPort struct {
+	ScriptedSerialPort
+	current     int
+	motorBaud   int
+	unsupported int
+	status      []byte
+}
+
+func (p *baudSimPort) SetBaudRate(baud int) error {
+	if baud == p.unsupported {
+		return errors.New("unsupported baud rate")
+	}
+	p.current = baud
+	return nil
+}
+
+func (p *baudSimPort) Write(b []byte) (int, error) {
+	n, err := p.MockSerialPort.Write(b)
+	if err != nil {
+		return n, err
+	}
+	// Answer only a Protocol 2.0 ping at the motor's baud rate
+	if p.current == p.motorBaud && len(b) >= 8 && b[2] == Header3 && b[7] == InstPing {
+		p.mu.Lock()
+		p.readBuf.Write(p.status)
+		p.mu.Unlock()
+	}
+	return n, nil
+}
+
+func TestDetectDevices(t *testing.T) {
+	port := &baudSimPort{
+		ScriptedSerialPort: *NewScriptedSerialPort(),
+		motorBaud:          1000000,
+		unsupported:        4000000,
+		status:             buildStatusPacket(7, 0, []byte{0x26, 0x04, 0x2D}),
+	}
+
+	detected, err := DetectDevices(context.Background(), port, DetectOptions{
+		BaudRates: []int{3000000, 4000000, 1000000},
+		Protocols: []Protocol{Protocol2},
+	})
+	if err != nil {
+		t.Fatalf("DetectDevices failed: %v", err)
+	}
+	if len(detected) != 1 {
+		t.Fatalf("Expected 1 device, got %+v", detected)
+	}
+	d := detected[0]
+	if d.ID != 7 || d.ModelNumber != 1062 || d.BaudRate != 1000000 || d.Protocol != 2 {
+		t.Errorf("Detected device mismatch: %+v", d)
+	}
+	if port.current != 1000000 {
+		t.Errorf("Port should be left at the last rate tried, got %d", port.current)
+	}
+}
+
+func TestDetectDevicesNoUsableBaud(t *testing.T) {
+	port := &baudSimPort{ScriptedSerialPort: *NewScriptedSerialPort(), unsupported: 4500000}
+
+	_, err := DetectDevices(context.Background(), port, DetectOptions{
+		BaudRates: []int{4500000},
+		Protocols: []Protocol{Protocol2},
+	})
+	if err == nil {
+		t.Error("Expected error when no baud rate can be configured")
+	}
+}
@@ -1,7 +1,6 @@
 package dxl
 
 import (
-	"bytes"
 	"encoding/binary"
 	"fmt"
 	"time"
@@ -15,6 +14,8 @@ const (
 	MinHeaderSize = 7 // Header(4) + ID(1) + Length(2)
 	// DefaultTimeout is the default timeout for packet read operations
 	DefaultTimeout = 100 * time.Millisecond
+	// DefaultReturnDelay is the factory Return Delay Time of X-series motors (250 x 2us)
+	DefaultReturnDelay = 500 * time.Microsecond
 )
 
 // SerialPortInterface defines the contract for serial port operations.
@@ -35,11 +36,56 @@ type SerialPortInterface interface {
 
 type Driver struct {
 	port    SerialPortInterface
+	proto   Protocol
 	Timeout time.Duration // Configurable timeout for read operations
+
+	// Bus timing used to size broadcast response windows (Scan).
+	// BaudRate 0 assumes 57600, the factory default.
+	BaudRate    int
+	ReturnDelay time.Duration
+
+	// rxBuf holds bytes received after the end of the last returned packet.
+	// Several status packets can arrive in a single port read (Sync/Bulk Read),
+	// so leftovers must be kept for the next readPacketWithTimeout call.
+	rxBuf []byte
+
+	// models caches the control table of each motor for ReadItem/WriteItem
+	models map[uint8]*ModelDefinition
 }
 
+// NewDriver creates a Protocol 2.0 driver on the given port
 func NewDriver(port SerialPortInterface) *Driver {
-	return &Driver{port: port, Timeout: DefaultTimeout}
+	return NewDriverWithProtocol(port, Protocol2)
+}
+
+// NewDriverWithProtocol creates a driver speaking the given protocol version
+func NewDriverWithProtocol(port SerialPortInterface, proto Protocol) *Driver {
+	return &Driver{port: port, proto: proto, Timeout: DefaultTimeout, ReturnDelay: DefaultReturnDelay}
+}
+
+// Protocol returns the protocol the driver speaks
+func (d *Driver) Protocol() Protocol {
+	return d.proto
+}
+
+// checkSupported returns an error if the driver's protocol lacks the instruction
+func (d *Driver) checkSupported(inst uint8, name string) error {
+	if !d.proto.supports(inst) {
+		return fmt.Errorf("%s is not supported by protocol %d.0", name, d.proto.Version())
+	}
+	return nil
+}
+
+// appendWord appends an address or length parameter in the protocol's width
+// (2 bytes little-endian for Protocol 2.0, 1 byte for Protocol 1.0).
+func (d *Driver) appendWord(dst []byte, v uint16) ([]byte, error) {
+	if d.proto.wordSize() == 1 {
+		if v > 0xFF {
+			return nil, fmt.Errorf("value %d exceeds 1-byte field of protocol %d.0", v, d.proto.Version())
+		}
+		return append(dst, byte(v)), nil
+	}
+	return append(dst, byte(v&0xFF), byte(v>>8)), nil
 }
 
 // findPacketStart finds the start index of a valid packet header (FF FF FD)
@@ -53,48 +99,62 @@ func findPacketStart(data []byte) int {
 	return -1
 }
 
+// takePacket extracts the first complete packet from the receive buffer.
+// Bytes preceding the packet header are discarded.
+// Returns nil if no complete packet is buffered yet.
+func (d *Driver) takePacket() []byte {
+	startIdx, totalLen := d.proto.frame(d.rxBuf)
+	if startIdx == -1 || totalLen == 0 {
+		return nil
+	}
+
+	pkt := make([]byte, totalLen-startIdx)
+	copy(pkt, d.rxBuf[startIdx:totalLen])
+	d.rxBuf = append(d.rxBuf[:0], d.rxBuf[totalLen:]...)
+	return pkt
+}
+
 // readPacketWithTimeout reads a complete Dynamixel packet from the serial port.
 // It accumulates bytes until a complete packet is received or timeout occurs.
 // Returns the complete packet bytes or an error if timeout/read failure occurs.
 func (d *Driver) readPacketWithTimeout(timeout time.Duration) ([]byte, error) {
 	deadline := time.Now().Add(timeout)
-	buf := bytes.NewBuffer(nil)
 	tmp := make([]byte, ReadBufferSize)
 
-	for time.Now().Before(deadline) {
+	for {
+		// A previous read may already have buffered the next packet
+		if pkt := d.takePacket(); pkt != nil {
+			return pkt, nil
+		}
+		if !time.Now().Before(deadline) {
+			break
+		}
+
 		n, err := d.port.Read(tmp)
 		if err != nil {
 			return nil, err
 		}
 		if n > 0 {
-			buf.Write(tmp[:n])
-
-			// Check if we have enough bytes for header + length fields
-			if buf.Len() >= MinHeaderSize {
-				b := buf.Bytes()
-				startIdx := findPacketStart(b)
-
-				if startIdx != -1 && buf.Len() >= startIdx+MinHeaderSize {
-					pkt := buf.Bytes()
-					bodyLen := uint16(pkt[startIdx+5]) | (uint16(pkt[startIdx+6]) << 8)
-					totalLen := startIdx + MinHeaderSize + int(bodyLen)
-
-					if buf.Len() >= totalLen {
-						return pkt[startIdx:totalLen], nil
-					}
-				}
-			}
+			d.rxBuf = append(d.rxBuf, tmp[:n]...)
 		}
 	}
 
-	return nil, fmt.Errorf("read timeout, buffered: %x", buf.Bytes())
+	return nil, fmt.Errorf("%w, buffered: %x", ErrTimeout, d.rxBuf)
+}
+
+// sendPacket writes an instruction packet to the port.
+// Stale bytes from a previous transaction are dropped so that the
+// following reads only see responses to this packet.
+func (d *Driver) sendPacket(txPacket []byte) error {
+	d.rxBuf = d.rxBuf[:0]
+	_, err := d.port.Write(txPacket)
+	return err
 }
 
 // Transfer sends a packet and waits for a response.
 // This is the fundamental request-response pattern for Dynamixel communication.
 func (d *Driver) Transfer(txPacket []byte) ([]byte, error) {
-	_, err := d.port.Write(txPacket)
-	if err != nil {
+	if err := d.sendPacket(txPacket); err != nil {
 		return nil, fmt.Errorf("write failed: %v", err)
 	}
 
@@ -102,70 +162,214 @@ func (d *Driver) Transfer(txPacket []byte) ([]byte, error) {
 }
 
 func (d *Driver) Write(id uint8, addr uint16, data []byte) error {
-	// Build Packet
-	params := make([]byte, 2+len(data))
-	binary.LittleEndian.PutUint16(params[0:], addr)
-	copy(params[2:], data)
+	return d.writeData(id, InstWrite, addr, data)
+}
+
+// RegWrite stages data at addr without applying it. The motor holds the
+// registered write until it receives an Action instruction, which lets several
+// motors start moving at the same moment even without Sync Write.
+func (d *Driver) RegWrite(id uint8, addr uint16, data []byte) error {
+	return d.writeData(id, InstRegWrite, addr, data)
+}
 
-	tx := BuildPacket(id, InstWrite, params)
+// Action executes the instruction previously staged with RegWrite.
+// Use BroadcastID to trigger every staged motor in one packet.
+func (d *Driver) Action(id uint8) error {
+	return d.sendInstruction(id, InstAction, nil)
+}
 
-	rx, err := d.Transfer(tx)
+// RegisteredInstruction reports whether a RegWrite is staged and waiting for Action
+// (Registered Instruction register, X-series: 69, PRO: 890).
+func (d *Driver) RegisteredInstruction(id uint8, addr uint16) (bool, error) {
+	data, err := d.Read(id, addr, 1)
+	if err != nil && !isAlertOnly(err) {
+		return false, err
+	}
+	if len(data) != 1 {
+		return false, fmt.Errorf("invalid length: %d", len(data))
+	}
+	return data[0] == 1, nil
+}
+
+// FactoryResetMode selects which settings survive a Factory Reset
+type FactoryResetMode uint8
+
+const (
+	ResetAll             FactoryResetMode = 0xFF // Reset everything, including ID and baud rate
+	ResetExceptID        FactoryResetMode = 0x01 // Keep the ID
+	ResetExceptIDAndBaud FactoryResetMode = 0x02 // Keep the ID and baud rate
+)
+
+// Clear instruction parameters (Protocol 2.0)
+var (
+	clearMultiTurnParams = []byte{0x01, 0x44, 0x58, 0x4C, 0x22} // 0x01 + "DXL\""
+	clearErrorParams     = []byte{0x02, 0x45, 0x52, 0x43, 0x4C} // 0x02 + "ERCL"
+)
+
+// Reboot restarts the motor. Torque is disabled afterwards and latched
+// hardware errors are cleared. The motor answers before rebooting; allow
+// some time before talking to it again.
+func (d *Driver) Reboot(id uint8) error {
+	if err := d.checkSupported(InstReboot, "reboot"); err != nil {
+		return err
+	}
+	return d.sendInstruction(id, InstReboot, nil)
+}
+
+// FactoryReset restores the control table to factory defaults.
+// Protocol 1.0 only supports ResetAll.
+func (d *Driver) FactoryReset(id uint8, mode FactoryResetMode) error {
+	if d.proto.Version() == 1 {
+		if mode != ResetAll {
+			return fmt.Errorf("protocol 1.0 factory reset only supports ResetAll")
+		}
+		return d.sendInstruction(id, InstFactoryReset, nil)
+	}
+	switch mode {
+	case ResetAll, ResetExceptID, ResetExceptIDAndBaud:
+	default:
+		return fmt.Errorf("invalid factory reset mode: 0x%02X", uint8(mode))
+	}
+	return d.sendInstruction(id, InstFactoryReset, []byte{byte(mode)})
+}
+
+// ClearMultiTurn resets the multi-turn revolution count so Present Position
+// returns to the single-turn range. Only accepted while the motor is stopped.
+func (d *Driver) ClearMultiTurn(id uint8) error {
+	if err := d.checkSupported(InstClear, "clear"); err != nil {
+		return err
+	}
+	return d.sendInstruction(id, InstClear, clearMultiTurnParams)
+}
+
+// ClearErrors clears latched errors without a reboot on firmware that
+// supports it (e.g. Y-series); older X-series firmware requires Reboot.
+func (d *Driver) ClearErrors(id uint8) error {
+	if err := d.checkSupported(InstClear, "clear"); err != nil {
+		return err
+	}
+	return d.sendInstruction(id, InstClear, clearErrorParams)
+}
+
+// writeData sends a Write-style instruction: [Addr, Data...]
+func (d *Driver) writeData(id uint8, inst uint8, addr uint16, data []byte) error {
+	// Build Packet: [Addr, Data...]
+	params, err := d.appendWord(make([]byte, 0, 2+len(data)), addr)
 	if err != nil {
 		return err
 	}
+	params = append(params, data...)
 
-	_, errCode, _, err := ParsePacket(rx)
+	return d.sendInstruction(id, inst, params)
+}
+
+// sendInstruction sends an instruction that answers with an empty status packet.
+// Broadcast instructions get no status, so only transmission is checked.
+func (d *Driver) sendInstruction(id uint8, inst uint8, params []byte) error {
+	tx := d.proto.BuildPacket(id, inst, params)
+
+	if id == BroadcastID {
+		if err := d.sendPacket(tx); err != nil {
+			return fmt.Errorf("write failed: %v", err)
+		}
+		return nil
+	}
+
+	rx, err := d.Transfer(tx)
 	if err != nil {
 		return err
 	}
-	if errCode != 0 {
-		return fmt.Errorf("dxl error code: %02X", errCode)
+
+	_, errCode, _, err := d.proto.ParsePacket(rx)
+	if err != nil {
+		return err
 	}
-	return nil
+	return d.proto.statusError(id, errCode)
 }
 
+// Read reads length bytes starting at addr.
+// If the motor answers with only the hardware alert flag set, the data is
+// still returned together with a *StatusError (Code == ErrCodeNone).
 func (d *Driver) Read(id uint8, addr uint16, length uint16) ([]byte, error) {
-	// Build Packet
-	params := make([]byte, 4)
-	binary.LittleEndian.PutUint16(params[0:], addr)
-	binary.LittleEndian.PutUint16(params[2:], length)
+	// Build Packet: [Addr, Length]
+	params, err := d.appendWord(make([]byte, 0, 4), addr)
+	if err != nil {
+		return nil, err
+	}
+	if params, err = d.appendWord(params, length); err != nil {
+		return nil, err
+	}
 
-	tx := BuildPacket(id, InstRead, params)
+	tx := d.proto.BuildPacket(id, InstRead, params)
 
 	rx, err := d.Transfer(tx)
 	if err != nil {
 		return nil, err
 	}
 
-	_, errCode, readParams, err := ParsePacket(rx)
+	_, errCode, readParams, err := d.proto.ParsePacket(rx)
 	if err != nil {
 		return nil, err
 	}
-	if errCode != 0 {
-		return nil, fmt.Errorf("dxl error code: %02X", errCode)
+	if err := d.proto.statusError(id, errCode); err != nil {
+		if isAlertOnly(err) {
+			return readParams, err
+		}
+		return nil, err
 	}
 	return readParams, nil
 }
 
+// DeviceInfo identifies a motor found on the bus
+type DeviceInfo struct {
+	ID          uint8
+	ModelNumber uint16
+	Firmware    uint8
+}
+
+// Ping checks that a motor responds and returns its model number.
 func (d *Driver) Ping(id uint8) (modelNum uint16, err error) {
-	tx := BuildPacket(id, InstPing, nil)
+	info, err := d.PingDevice(id)
+	return info.ModelNumber, err
+}
+
+// PingDevice pings a motor and returns its model number and firmware version.
+// Protocol 1.0 ping carries no data, so both are read from addresses 0-2.
+func (d *Driver) PingDevice(id uint8) (DeviceInfo, error) {
+	tx := d.proto.BuildPacket(id, InstPing, nil)
 	rx, err := d.Transfer(tx)
 	if err != nil {
-		return 0, err
+		return DeviceInfo{}, err
 	}
 
-	_, errCode, params, err := ParsePacket(rx)
+	_, errCode, params, err := d.proto.ParsePacket(rx)
 	if err != nil {
-		return 0, err
+		return DeviceInfo{}, err
 	}
-	if errCode != 0 {
-		return 0, fmt.Errorf("dxl error code: %02X", errCode)
+	if err := d.proto.statusError(id, errCode); err != nil {
+		return DeviceInfo{}, err
 	}
 
-	if len(params) >= 3 {
-		modelNum = binary.LittleEndian.Uint16(params[0:])
+	if d.proto.Version() == 1 {
+		data, err := d.Read(id, 0, 3)
+		if err != nil {
+			return DeviceInfo{}, fmt.Errorf("read model number: %w", err)
+		}
+		params = data
 	}
-	return modelNum, nil
+	return parsePingParams(id, params)
+}
+
+// parsePingParams decodes ping data: [Model_L, Model_H, Firmware]
+func parsePingParams(id uint8, params []byte) (DeviceInfo, error) {
+	if len(params) < 3 {
+		return DeviceInfo{}, fmt.Errorf("invalid ping response length: %d", len(params))
+	}
+	return DeviceInfo{
+		ID:          id,
+		ModelNumber: binary.LittleEndian.Uint16(params[0:]),
+		Firmware:    params[2],
+	}, nil
 }
 
 // Write4Byte Helper
@@ -175,6 +379,14 @@ func (d *Driver) Write4Byte(id uint8, addr uint16, val uint32) error {
 	return d.Write(id, addr, buf)
 }
 
+// Write2Byte Helper. Signed values (Goal Current, Goal PWM) are passed as
+// uint16(int16(v)).
+func (d *Driver) Write2Byte(id uint8, addr uint16, val uint16) error {
+	buf := make([]byte, 2)
+	binary.LittleEndian.PutUint16(buf, val)
+	return d.Write(id, addr, buf)
+}
+
 // Read4Byte Helper
 func (d *Driver) Read4Byte(id uint8, addr uint16) (uint32, error) {
 	data, err := d.Read(id, addr, 4)
@@ -210,10 +422,15 @@ func (d *Driver) SyncWrite(addr uint16, dataLength uint16, motors []SyncWriteDat
 
 	// Pre-allocate buffer with exact size to avoid reallocations
 	// Format: [Addr_L, Addr_H, Len_L, Len_H, [ID1, Data1...], [ID2, Data2...], ...]
+	// (Protocol 1.0 uses single-byte Addr and Len)
 	totalSize := 4 + len(motors)*(1+int(dataLength))
-	params := make([]byte, 4, totalSize)
-	binary.LittleEndian.PutUint16(params[0:], addr)
-	binary.LittleEndian.PutUint16(params[2:], dataLength)
+	params, err := d.appendWord(make([]byte, 0, totalSize), addr)
+	if err != nil {
+		return err
+	}
+	if params, err = d.appendWord(params, dataLength); err != nil {
+		return err
+	}
 
 	// Append motor data efficiently
 	for _, m := range motors {
@@ -222,9 +439,9 @@ func (d *Driver) SyncWrite(addr uint16, dataLength uint16, motors []SyncWriteDat
 	}
 
 	// Use broadcast ID (0xFE) - no status response expected
-	tx := BuildPacket(0xFE, InstSyncWrite, params)
+	tx := d.proto.BuildPacket(BroadcastID, InstSyncWrite, params)
 
-	_, err := d.port.Write(tx)
+	err = d.sendPacket(tx)
 	if err != nil {
 		return fmt.Errorf("sync write failed: %v", err)
 	}
@@ -246,7 +463,19 @@ func (d *Driver) SyncWrite4Byte(addr uint16, values map[uint8]uint32) error {
 	return d.SyncWrite(addr, 4, motors)
 }
 
-// SyncReadData represents expected data for a motor in sync read
+// SyncWrite2Byte writes 2-byte values to multiple motors
+func (d *Driver) SyncWrite2Byte(addr uint16, values map[uint8]uint16) error {
+	motors := make([]SyncWriteData, 0, len(values))
+	for id, val := range values {
+		data := make([]byte, 2)
+		binary.LittleEndian.PutUint16(data, val)
+		motors = append(motors, SyncWriteData{ID: id, Data: data})
+	}
+	return d.SyncWrite(addr, 2, motors)
+}
+
+// SyncReadData represents expected data for a motor in sync read.
+// When Err is an alert-only *StatusError, Data is still valid.
 type SyncReadData struct {
 	ID   uint8
 	Data []byte
@@ -255,6 +484,9 @@ type SyncReadData struct {
 
 // SyncRead reads same address from multiple motors
 func (d *Driver) SyncRead(addr uint16, dataLength uint16, ids []uint8) ([]SyncReadData, error) {
+	if err := d.checkSupported(InstSyncRead, "sync read"); err != nil {
+		return nil, err
+	}
 	if len(ids) == 0 {
 		return nil, fmt.Errorf("no motor IDs provided")
 	}
@@ -266,10 +498,10 @@ func (d *Driver) SyncRead(addr uint16, dataLength uint16, ids []uint8) ([]SyncRe
 	copy(params[4:], ids)
 
 	// Use broadcast ID for sync read request
-	tx := BuildPacket(0xFE, InstSyncRead, params)
+	tx := d.proto.BuildPacket(BroadcastID, InstSyncRead, params)
 
 	// Send request
-	_, err := d.port.Write(tx)
+	err := d.sendPacket(tx)
 	if err != nil {
 		return nil, fmt.Errorf("sync read tx failed: %v", err)
 	}
@@ -281,17 +513,18 @@ func (d *Driver) SyncRead(addr uint16, dataLength uint16, ids []uint8) ([]SyncRe
 
 		rx, err := d.readPacketWithTimeout(d.Timeout)
 		if err != nil {
-			results[i].Err = fmt.Errorf("timeout waiting for motor %d: %v", id, err)
+			results[i].Err = fmt.Errorf("timeout waiting for motor %d: %w", id, err)
 			continue
 		}
 
-		_, errCode, readParams, err := ParsePacket(rx)
+		_, errCode, readParams, err := d.proto.ParsePacket(rx)
 		if err != nil {
 			results[i].Err = err
-		} else if errCode != 0 {
-			results[i].Err = fmt.Errorf("motor error code: %02X", errCode)
 		} else {
-			results[i].Data = readParams
+			results[i].Err = d.proto.statusError(id, errCode)
+			if results[i].Err == nil || isAlertOnly(results[i].Err) {
+				results[i].Data = readParams
+			}
 		}
 	}
 
@@ -306,12 +539,17 @@ func (d *Driver) SyncRead4Byte(addr uint16, ids []uint8) (map[uint8]uint32, erro
 	if err != nil {
 		return nil, err
 	}
+	return collect4Byte(results)
+}
 
+// collect4Byte converts per-motor read results into a map of 4-byte values.
+// Motors with errors are left out; an error is returned only if none succeeded.
+func collect4Byte(results []SyncReadData) (map[uint8]uint32, error) {
 	values := make(map[uint8]uint32)
 	var lastErr error
 	for _, r := range results {
-		if r.Err != nil {
-			lastErr = fmt.Errorf("motor %d error: %v", r.ID, r.Err)
+		if r.Err != nil && !isAlertOnly(r.Err) {
+			lastErr = fmt.Errorf("motor %d error: %w", r.ID, r.Err)
 			continue
 		}
 		if len(r.Data) != 4 {
@@ -327,3 +565,313 @@ func (d *Driver) SyncRead4Byte(addr uint16, ids []uint8) (map[uint8]uint32, erro
 
 	return values, nil
 }
+
+// BulkReadRequest describes the register range to read from a single motor
+// in a bulk read. Unlike sync read, each motor can use its own address and length.
+type BulkReadRequest struct {
+	ID     uint8
+	Addr   uint16
+	Length uint16
+}
+
+// BulkRead reads a different address/length from each motor in a single packet.
+// Results are returned in request order. Like SyncRead, a motor that fails to
+// respond only sets Err on its own entry; the returned error is reserved for
+// invalid requests and transmit failures.
+func (d *Driver) BulkRead(requests []BulkReadRequest) ([]SyncReadData, error) {
+	if len(requests) == 0 {
+		return nil, fmt.Errorf("no read requests provided")
+	}
+
+	// Protocol 2.0: [ID1, Addr_L, Addr_H, Len_L, Len_H, ID2, ...]
+	// Protocol 1.0: [0x00, Len1, ID1, Addr1, Len2, ID2, Addr2, ...]
+	params := make([]byte, 0, 1+len(requests)*5)
+	if d.proto.Version() == 1 {
+		params = append(params, 0x00)
+	}
+	seen := make(map[uint8]bool, len(requests))
+	for _, r := range requests {
+		if seen[r.ID] {
+			return nil, fmt.Errorf("motor ID %d: duplicate bulk read request", r.ID)
+		}
+		seen[r.ID] = true
+		if d.proto.Version() == 1 {
+			if r.Addr > 0xFF || r.Length > 0xFF {
+				return nil, fmt.Errorf("motor ID %d: address/length exceed protocol 1.0 range", r.ID)
+			}
+			params = append(params, byte(r.Length), r.ID, byte(r.Addr))
+			continue
+		}
+		params = append(params, r.ID,
+			byte(r.Addr&0xFF), byte(r.Addr>>8),
+			byte(r.Length&0xFF), byte(r.Length>>8))
+	}
+
+	tx := d.proto.BuildPacket(BroadcastID, InstBulkRead, params)
+
+	if err := d.sendPacket(tx); err != nil {
+		return nil, fmt.Errorf("bulk read tx failed: %v", err)
+	}
+
+	// Motors answer in request order, one status packet each. Replies are
+	// matched to requests by ID, so a missing or stray reply does not shift
+	// the ones after it.
+	results := make([]SyncReadData, len(requests))
+	slot := make(map[uint8]int, len(requests))
+	for i, r := range requests {
+		results[i].ID = r.ID
+		slot[r.ID] = i
+	}
+	answered := make([]bool, len(requests))
+	pending := len(requests)
+	reads := len(requests) // Extra reads are granted for stray packets, up to twice
+	var lastErr error
+	for n := 0; pending > 0 && n < reads; n++ {
+		rx, err := d.readPacketWithTimeout(d.Timeout)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		id, errCode, readParams, err := d.proto.ParsePacket(rx)
+		i, ok := slot[id]
+		if err != nil || !ok || answered[i] {
+			if err == nil {
+				err = fmt.Errorf("unexpected status from motor %d", id)
+			}
+			lastErr = err
+			if reads < 2*len(requests) {
+				reads++
+			}
+			continue
+		}
+		answered[i] = true
+		pending--
+
+		r := requests[i]
+		if len(readParams) != int(r.Length) {
+			results[i].Err = fmt.Errorf("invalid data length %d (expected %d)", len(readParams), r.Length)
+			continue
+		}
+		results[i].Err = d.proto.statusError(id, errCode)
+		if results[i].Err == nil || isAlertOnly(results[i].Err) {
+			results[i].Data = readParams
+		}
+	}
+
+	for i, r := range requests {
+		if !answered[i] {
+			results[i].Err = fmt.Errorf("no status from motor %d: %w", r.ID, lastErr)
+		}
+	}
+
+	return results, nil
+}
+
+// BulkWriteData represents data written to a single motor in a bulk write.
+// Each motor can target its own address; the data length is taken from Data.
+type BulkWriteData struct {
+	ID   uint8
+	Addr uint16
+	Data []byte
+}
+
+// BulkWrite writes a different address/length to each motor in a single packet.
+// Like SyncWrite it is broadcast, so no status response is expected.
+func (d *Driver) BulkWrite(motors []BulkWriteData) error {
+	if err := d.checkSupported(InstBulkWrite, "bulk write"); err != nil {
+		return err
+	}
+	if len(motors) == 0 {
+		return fmt.Errorf("no motors provided")
+	}
+
+	// Format: [ID1, Addr_L, Addr_H, Len_L, Len_H, Data1..., ID2, ...]
+	totalSize := 0
+	seen := make(map[uint8]bool, len(motors))
+	for _, m := range motors {
+		if seen[m.ID] {
+			return fmt.Errorf("motor ID %d: duplicate bulk write entry", m.ID)
+		}
+		if len(m.Data) == 0 {
+			return fmt.Errorf("motor ID %d: empty data", m.ID)
+		}
+		seen[m.ID] = true
+		totalSize += 5 + len(m.Data)
+	}
+
+	params := make([]byte, 0, totalSize)
+	for _, m := range motors {
+		length := uint16(len(m.Data))
+		params = append(params, m.ID,
+			byte(m.Addr&0xFF), byte(m.Addr>>8),
+			byte(length&0xFF), byte(length>>8))
+		params = append(params, m.Data...)
+	}
+
+	tx := d.proto.BuildPacket(BroadcastID, InstBulkWrite, params)
+
+	if err := d.sendPacket(tx); err != nil {
+		return fmt.Errorf("bulk write failed: %v", err)
+	}
+
+	// Small delay to ensure packet transmission completes
+	time.Sleep(time.Millisecond)
+
+	return nil
+}
+
+// FastSyncRead reads the same address from multiple motors using the
+// Fast Sync Read instruction (0x8A). All motors answer in one concatenated
+// status packet, so the bus waits for a single response instead of one per motor.
+// Requires firmware with Fast Sync Read support (e.g. recent X-series).
+func (d *Driver) FastSyncRead(addr uint16, dataLength uint16, ids []uint8) ([]SyncReadData, error) {
+	if err := d.checkSupported(InstFastSyncRead, "fast sync read"); err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("no motor IDs provided")
+	}
+
+	// Same parameter layout as Sync Read: [Addr_L, Addr_H, Len_L, Len_H, ID1, ID2, ...]
+	params := make([]byte, 4+len(ids))
+	binary.LittleEndian.PutUint16(params[0:], addr)
+	binary.LittleEndian.PutUint16(params[2:], dataLength)
+	copy(params[4:], ids)
+
+	tx := d.proto.BuildPacket(BroadcastID, InstFastSyncRead, params)
+
+	if err := d.sendPacket(tx); err != nil {
+		return nil, fmt.Errorf("fast sync read tx failed: %v", err)
+	}
+
+	lengths := make([]uint16, len(ids))
+	for i := range lengths {
+		lengths[i] = dataLength
+	}
+	return d.readFastStatus(ids, lengths), nil
+}
+
+// FastSyncRead4Byte reads 4-byte values from multiple motors using Fast Sync Read.
+// Follows the same partial-result semantics as SyncRead4Byte.
+func (d *Driver) FastSyncRead4Byte(addr uint16, ids []uint8) (map[uint8]uint32, error) {
+	results, err := d.FastSyncRead(addr, 4, ids)
+	if err != nil {
+		return nil, err
+	}
+	return collect4Byte(results)
+}
+
+// FastBulkRead reads a different address/length from each motor using the
+// Fast Bulk Read instruction (0x9A), with all motors answering in one status packet.
+func (d *Driver) FastBulkRead(requests []BulkReadRequest) ([]SyncReadData, error) {
+	if err := d.checkSupported(InstFastBulkRead, "fast bulk read"); err != nil {
+		return nil, err
+	}
+	if len(requests) == 0 {
+		return nil, fmt.Errorf("no read requests provided")
+	}
+
+	// Same parameter layout as Bulk Read: [ID1, Addr_L, Addr_H, Len_L, Len_H, ID2, ...]
+	params := make([]byte, 0, len(requests)*5)
+	ids := make([]uint8, len(requests))
+	lengths := make([]uint16, len(requests))
+	seen := make(map[uint8]bool, len(requests))
+	for i, r := range requests {
+		if seen[r.ID] {
+			return nil, fmt.Errorf("motor ID %d: duplicate bulk read request", r.ID)
+		}
+		seen[r.ID] = true
+		ids[i] = r.ID
+		lengths[i] = r.Length
+		params = append(params, r.ID,
+			byte(r.Addr&0xFF), byte(r.Addr>>8),
+			byte(r.Length&0xFF), byte(r.Length>>8))
+	}
+
+	tx := d.proto.BuildPacket(BroadcastID, InstFastBulkRead, params)
+
+	if err := d.sendPacket(tx); err != nil {
+		return nil, fmt.Errorf("fast bulk read tx failed: %v", err)
+	}
+
+	return d.readFastStatus(ids, lengths), nil
+}
+
+// readFastStatus receives the combined status packet of a Fast Sync/Bulk Read
+// and splits it into per-motor results.
+//
+// After the instruction byte the packet holds one block per motor:
+//
+//	[Err1, ID1, Data1..., CRC1_L, CRC1_H, Err2, ID2, Data2..., CRC2_L, CRC2_H, ...]
+//
+// The last block's CRC is the packet CRC, which ParsePacket already verified.
+// A packet-level failure (timeout, CRC) is reported on every motor's entry.
+func (d *Driver) readFastStatus(ids []uint8, lengths []uint16) []SyncReadData {
+	results := make([]SyncReadData, len(ids))
+	for i, id := range ids {
+		results[i].ID = id
+	}
+
+	failAll := func(err error) []SyncReadData {
+		for i := range results {
+			results[i].Err = err
+		}
+		return results
+	}
+
+	rx, err := d.readPacketWithTimeout(d.Timeout)
+	if err != nil {
+		return failAll(fmt.Errorf("timeout waiting for fast read status: %w", err))
+	}
+
+	_, errCode, params, err := d.proto.ParsePacket(rx)
+	if err != nil {
+		return failAll(err)
+	}
+
+	// ParsePacket splits off the first motor's error byte; put it back
+	body := make([]byte, 0, 1+len(params))
+	body = append(body, errCode)
+	body = append(body, params...)
+
+	offset := 0
+	for i, id := range ids {
+		blockLen := 2 + int(lengths[i])
+		if offset+blockLen > len(body) {
+			results[i].Err = fmt.Errorf("no data for motor %d in fast read status", id)
+			offset = len(body)
+			continue
+		}
+
+		motorErr := body[offset]
+		motorID := body[offset+1]
+		data := body[offset+2 : offset+blockLen]
+		offset += blockLen + 2 // Skip the per-block CRC
+
+		if motorID != id {
+			results[i].Err = fmt.Errorf("unexpected block from motor %d (expected %d)", motorID, id)
+			continue
+		}
+		results[i].Err = d.proto.statusError(id, motorErr)
+		if results[i].Err == nil || isAlertOnly(results[i].Err) {
+			results[i].Data = data
+		}
+	}
+
+	return results
+}
+
+// ReadHardwareError reads and decodes the Hardware Error Status register
+// (X-series: 70, PRO: 892). The alert flag that accompanies a latched
+// hardware error is expected here and not reported as an error.
+func (d *Driver) ReadHardwareError(id uint8, addr uint16) (HardwareError, error) {
+	data, err := d.Read(id, addr, 1)
+	if err != nil && !isAlertOnly(err) {
+		return 0, err
+	}
+	if len(data) != 1 {
+		return 0, fmt.Errorf("invalid length: %d", len(data))
+	}
+	return HardwareError(data[0]), nil
+}
@@ -3,6 +3,7 @@ package dxl
 import (
 	"bytes"
 	"errors"
+	"io"
 	"sync"
 	"testing"
 	"time"
@@ -91,6 +92,44 @@ func (m *MockSerialPort) SetWriteError(err error) {
 	m.writeErr = err
 }
 
+// ScriptedSerialPort answers each written packet with the next queued response,
+// like a device that only replies after receiving an instruction.
+// An empty response simulates a broadcast instruction with no status packet.
+// Like a real port, Read returns no data (instead of EOF) when nothing is pending.
+type ScriptedSerialPort struct {
+	MockSerialPort
+	responses [][]byte
+}
+
+func (s *ScriptedSerialPort) Read(b []byte) (int, error) {
+	n, err := s.MockSerialPort.Read(b)
+	if err == io.EOF {
+		return 0, nil
+	}
+	return n, err
+}
+
+func NewScriptedSerialPort(responses ...[]byte) *ScriptedSerialPort {
+	return &ScriptedSerialPort{
+		MockSerialPort: *NewMockSerialPort(),
+		responses:      responses,
+	}
+}
+
+func (s *ScriptedSerialPort) Write(b []byte) (int, error) {
+	n, err := s.MockSerialPort.Write(b)
+	if err != nil {
+		return n, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.responses) > 0 {
+		s.readBuf.Write(s.responses[0])
+		s.responses = s.responses[1:]
+	}
+	return n, nil
+}
+
 // buildStatusPacket creates a valid status response packet
 func buildStatusPacket(id uint8, errCode uint8, params []byte) []byte {
 	// Header: FF FF FD 00
@@ -424,3 +463,487 @@ func TestReadPacketWithGarbage(t *testing.T) {
 		t.Errorf("Data length: got %d, want 4", len(data))
 	}
 }
+
+func TestBulkRead(t *testing.T) {
+	mock := NewMockSerialPort()
+	driver := NewDriver(mock)
+
+	// Motor 1: present position (4 bytes), Motor 2: present current (2 bytes)
+	motor1Response := buildStatusPacket(1, 0, []byte{0x00, 0x08, 0x00, 0x00})
+	motor2Response := buildStatusPacket(2, 0, []byte{0x64, 0x00})
+	mock.SetResponse(append(motor1Response, motor2Response...))
+
+	results, err := driver.BulkRead([]BulkReadRequest{
+		{ID: 1, Addr: 132, Length: 4},
+		{ID: 2, Addr: 126, Length: 2},
+	})
+	if err != nil {
+		t.Fatalf("BulkRead failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results))
+	}
+	if results[0].Err != nil || !bytes.Equal(results[0].Data, []byte{0x00, 0x08, 0x00, 0x00}) {
+		t.Errorf("Motor 1 result: data=%X err=%v", results[0].Data, results[0].Err)
+	}
+	if results[1].Err != nil || !bytes.Equal(results[1].Data, []byte{0x64, 0x00}) {
+		t.Errorf("Motor 2 result: data=%X err=%v", results[1].Data, results[1].Err)
+	}
+
+	// Verify request format: broadcast, bulk read, per-motor addr/len
+	written := mock.GetWritten()
+	if written[4] != 0xFE {
+		t.Errorf("Expected broadcast ID 0xFE, got %02X", written[4])
+	}
+	if written[7] != InstBulkRead {
+		t.Errorf("Expected BulkRead instruction, got %02X", written[7])
+	}
+	expectedParams := []byte{1, 0x84, 0x00, 0x04, 0x00, 2, 0x7E, 0x00, 0x02, 0x00}
+	if !bytes.Equal(written[8:len(written)-2], expectedParams) {
+		t.Errorf("Params mismatch: got %X, want %X", written[8:len(written)-2], expectedParams)
+	}
+}
+
+func TestBulkReadPartialFailure(t *testing.T) {
+	mock := NewMockSerialPort()
+	driver := NewDriver(mock)
+
+	// Only motor 1 answers
+	mock.SetResponse(buildStatusPacket(1, 0, []byte{0x00, 0x08, 0x00, 0x00}))
+
+	results, err := driver.BulkRead([]BulkReadRequest{
+		{ID: 1, Addr: 132, Length: 4},
+		{ID: 2, Addr: 126, Length: 2},
+	})
+	if err != nil {
+		t.Fatalf("BulkRead should not fail on partial response: %v", err)
+	}
+	if results[0].Err != nil {
+		t.Errorf("Motor 1 should succeed, got %v", results[0].Err)
+	}
+	if results[1].Err == nil {
+		t.Error("Motor 2 should report an error")
+	}
+}
+
+func TestBulkReadMatchesRepliesByID(t *testing.T) {
+	// Motor 1 is silent, a stray status comes first and motor 3 answers
+	// before motor 2: each reply must still land in its own entry
+	var replies []byte
+	replies = append(replies, buildStatusPacket(9, 0, []byte{0x01})...)
+	replies = append(replies, buildStatusPacket(3, 0, []byte{0x78, 0x56})...)
+	replies = append(replies, buildStatusPacket(2, 0, []byte{0x34, 0x12})...)
+	mock := NewScriptedSerialPort(replies)
+	driver := NewDriver(mock)
+
+	results, err := driver.BulkRead([]BulkReadRequest{
+		{ID: 1, Addr: 132, Length: 4},
+		{ID: 2, Addr: 126, Length: 2},
+		{ID: 3, Addr: 126, Length: 2},
+	})
+	if err != nil {
+		t.Fatalf("BulkRead failed: %v", err)
+	}
+	if results[0].ID != 1 || results[0].Err == nil {
+		t.Errorf("Motor 1 should report an error, got %+v", results[0])
+	}
+	if results[1].ID != 2 || results[1].Err != nil || !bytes.Equal(results[1].Data, []byte{0x34, 0x12}) {
+		t.Errorf("Motor 2 = %+v, want data 3412", results[1])
+	}
+	if results[2].ID != 3 || results[2].Err != nil || !bytes.Equal(results[2].Data, []byte{0x78, 0x56}) {
+		t.Errorf("Motor 3 = %+v, want data 7856", results[2])
+	}
+}
+
+func TestBulkReadDuplicateID(t *testing.T) {
+	mock := NewMockSerialPort()
+	driver := NewDriver(mock)
+
+	_, err := driver.BulkRead([]BulkReadRequest{
+		{ID: 1, Addr: 132, Length: 4},
+		{ID: 1, Addr: 126, Length: 2},
+	})
+	if err == nil {
+		t.Error("Expected error for duplicate IDs, got nil")
+	}
+}
+
+func TestBulkWrite(t *testing.T) {
+	mock := NewMockSerialPort()
+	driver := NewDriver(mock)
+
+	err := driver.BulkWrite([]BulkWriteData{
+		{ID: 1, Addr: 116, Data: []byte{0x00, 0x08, 0x00, 0x00}},
+		{ID: 2, Addr: 102, Data: []byte{0x64, 0x00}},
+	})
+	if err != nil {
+		t.Fatalf("BulkWrite failed: %v", err)
+	}
+
+	written := mock.GetWritten()
+	if written[7] != InstBulkWrite {
+		t.Errorf("Expected BulkWrite instruction, got %02X", written[7])
+	}
+	expectedParams := []byte{
+		1, 0x74, 0x00, 0x04, 0x00, 0x00, 0x08, 0x00, 0x00,
+		2, 0x66, 0x00, 0x02, 0x00, 0x64, 0x00,
+	}
+	if !bytes.Equal(written[8:len(written)-2], expectedParams) {
+		t.Errorf("Params mismatch: got %X, want %X", written[8:len(written)-2], expectedParams)
+	}
+}
+
+func TestBulkWriteNoMotors(t *testing.T) {
+	mock := NewMockSerialPort()
+	driver := NewDriver(mock)
+
+	if err := driver.BulkWrite(nil); err == nil {
+		t.Error("Expected error for empty motors, got nil")
+	}
+}
+
+func TestReadPacketKeepsLeftoverBytes(t *testing.T) {
+	mock := NewMockSerialPort()
+	driver := NewDriver(mock)
+
+	// Both packets arrive in a single port read
+	first := buildStatusPacket(1, 0, []byte{0x01})
+	second := buildStatusPacket(2, 0, []byte{0x02})
+	mock.SetResponse(append(first, second...))
+
+	pkt1, err := driver.readPacketWithTimeout(driver.Timeout)
+	if err != nil || !bytes.Equal(pkt1, first) {
+		t.Fatalf("First packet: got %X, err %v", pkt1, err)
+	}
+	pkt2, err := driver.readPacketWithTimeout(driver.Timeout)
+	if err != nil || !bytes.Equal(pkt2, second) {
+		t.Fatalf("Second packet: got %X, err %v", pkt2, err)
+	}
+}
+
+// buildFastStatusPacket creates a Fast Sync/Bulk Read status packet.
+// Each block is [Err, ID, Data..., CRC_L, CRC_H]; the last block's CRC is the packet CRC.
+func buildFastStatusPacket(ids []uint8, errCodes []uint8, data [][]byte) []byte {
+	var body []byte
+	for i, id := range ids {
+		body = append(body, errCodes[i], id)
+		body = append(body, data[i]...)
+		if i < len(ids)-1 {
+			body = append(body, 0x00, 0x00) // Per-block CRC (not checked by the driver)
+		}
+	}
+
+	length := 1 + len(body) + 2
+	pkt := []byte{0xFF, 0xFF, 0xFD, 0x00, 0xFE}
+	pkt = append(pkt, byte(length&0xFF), byte((length>>8)&0xFF))
+	pkt = append(pkt, InstStatus)
+	pkt = append(pkt, body...)
+
+	crc := UpdateCRC(0, pkt)
+	pkt = append(pkt, byte(crc&0xFF), byte((crc>>8)&0xFF))
+
+	return pkt
+}
+
+func TestFastSyncRead(t *testing.T) {
+	mock := NewMockSerialPort()
+	driver := NewDriver(mock)
+
+	mock.SetResponse(buildFastStatusPacket(
+		[]uint8{1, 2},
+		[]uint8{0, 0},
+		[][]byte{{0x00, 0x08, 0x00, 0x00}, {0x00, 0x10, 0x00, 0x00}},
+	))
+
+	values, err := driver.FastSyncRead4Byte(132, []uint8{1, 2})
+	if err != nil {
+		t.Fatalf("FastSyncRead4Byte failed: %v", err)
+	}
+	if values[1] != 2048 || values[2] != 4096 {
+		t.Errorf("Values mismatch: got %v", values)
+	}
+
+	written := mock.GetWritten()
+	if written[7] != InstFastSyncRead {
+		t.Errorf("Expected FastSyncRead instruction, got %02X", written[7])
+	}
+}
+
+func TestFastSyncReadMotorError(t *testing.T) {
+	mock := NewMockSerialPort()
+	driver := NewDriver(mock)
+
+	mock.SetResponse(buildFastStatusPacket(
+		[]uint8{1, 2},
+		[]uint8{0, 0x04},
+		[][]byte{{0x00, 0x08, 0x00, 0x00}, {0x00, 0x00, 0x00, 0x00}},
+	))
+
+	results, err := driver.FastSyncRead(132, 4, []uint8{1, 2})
+	if err != nil {
+		t.Fatalf("FastSyncRead failed: %v", err)
+	}
+	if results[0].Err != nil {
+		t.Errorf("Motor 1 should succeed, got %v", results[0].Err)
+	}
+	if results[1].Err == nil {
+		t.Error("Motor 2 should report its error code")
+	}
+}
+
+func TestFastSyncReadTimeout(t *testing.T) {
+	mock := NewMockSerialPort()
+	driver := NewDriver(mock)
+
+	results, err := driver.FastSyncRead(132, 4, []uint8{1, 2})
+	if err != nil {
+		t.Fatalf("FastSyncRead should report per-motor errors, got %v", err)
+	}
+	for _, r := range results {
+		if r.Err == nil {
+			t.Errorf("Motor %d: expected error on timeout", r.ID)
+		}
+	}
+}
+
+func TestFastBulkRead(t *testing.T) {
+	mock := NewMockSerialPort()
+	driver := NewDriver(mock)
+
+	mock.SetResponse(buildFastStatusPacket(
+		[]uint8{1, 2},
+		[]uint8{0, 0},
+		[][]byte{{0x00, 0x08, 0x00, 0x00}, {0x64, 0x00}},
+	))
+
+	results, err := driver.FastBulkRead([]BulkReadRequest{
+		{ID: 1, Addr: 132, Length: 4},
+		{ID: 2, Addr: 126, Length: 2},
+	})
+	if err != nil {
+		t.Fatalf("FastBulkRead failed: %v", err)
+	}
+	if results[0].Err != nil || !bytes.Equal(results[0].Data, []byte{0x00, 0x08, 0x00, 0x00}) {
+		t.Errorf("Motor 1 result: data=%X err=%v", results[0].Data, results[0].Err)
+	}
+	if results[1].Err != nil || !bytes.Equal(results[1].Data, []byte{0x64, 0x00}) {
+		t.Errorf("Motor 2 result: data=%X err=%v", results[1].Data, results[1].Err)
+	}
+
+	written := mock.GetWritten()
+	if written[7] != InstFastBulkRead {
+		t.Errorf("Expected FastBulkRead instruction, got %02X", written[7])
+	}
+}
+
+func TestDriverProtocol1Ping(t *testing.T) {
+	// Ping status (no params) followed by model number/firmware read (AX-12A = 12)
+	mock := NewScriptedSerialPort(
+		buildStatusPacket1(1, 0, nil),
+		buildStatusPacket1(1, 0, []byte{0x0C, 0x00, 0x18}),
+	)
+	driver := NewDriverWithProtocol(mock, Protocol1)
+
+	model, err := driver.Ping(1)
+	if err != nil {
+		t.Fatalf("Ping failed: %v", err)
+	}
+	if model != 12 {
+		t.Errorf("Model mismatch: got %d, want 12", model)
+	}
+}
+
+func TestDriverProtocol1ReadWrite(t *testing.T) {
+	mock := NewMockSerialPort()
+	driver := NewDriverWithProtocol(mock, Protocol1)
+
+	mock.SetResponse(buildStatusPacket1(1, 0, []byte{0x00, 0x02}))
+	data, err := driver.Read(1, 36, 2)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if !bytes.Equal(data, []byte{0x00, 0x02}) {
+		t.Errorf("Data mismatch: got %X", data)
+	}
+	if !bytes.Equal(mock.GetWritten(), []byte{0xFF, 0xFF, 0x01, 0x04, 0x02, 0x24, 0x02, 0xD2}) {
+		t.Errorf("Read packet mismatch: got %X", mock.GetWritten())
+	}
+
+	// Addresses beyond one byte cannot be encoded
+	if err := driver.Write(1, 300, []byte{1}); err == nil {
+		t.Error("Expected error for 2-byte address in protocol 1.0")
+	}
+}
+
+func TestDriverProtocol1SyncWrite(t *testing.T) {
+	mock := NewMockSerialPort()
+	driver := NewDriverWithProtocol(mock, Protocol1)
+
+	err := driver.SyncWrite(30, 2, []SyncWriteData{
+		{ID: 1, Data: []byte{0x00, 0x02}},
+		{ID: 2, Data: []byte{0x00, 0x01}},
+	})
+	if err != nil {
+		t.Fatalf("SyncWrite failed: %v", err)
+	}
+
+	written := mock.GetWritten()
+	// FF FF FE LEN 83 [30, 2, 1, 00 02, 2, 00 01] CHK
+	expectedParams := []byte{30, 2, 1, 0x00, 0x02, 2, 0x00, 0x01}
+	if written[4] != InstSyncWrite || !bytes.Equal(written[5:len(written)-1], expectedParams) {
+		t.Errorf("SyncWrite packet mismatch: got %X", written)
+	}
+}
+
+func TestDriverProtocol1BulkRead(t *testing.T) {
+	mock := NewMockSerialPort()
+	driver := NewDriverWithProtocol(mock, Protocol1)
+
+	response := buildStatusPacket1(1, 0, []byte{0x00, 0x02})
+	response = append(response, buildStatusPacket1(2, 0, []byte{0x20})...)
+	mock.SetResponse(response)
+
+	results, err := driver.BulkRead([]BulkReadRequest{
+		{ID: 1, Addr: 36, Length: 2},
+		{ID: 2, Addr: 43, Length: 1},
+	})
+	if err != nil {
+		t.Fatalf("BulkRead failed: %v", err)
+	}
+	for _, r := range results {
+		if r.Err != nil {
+			t.Errorf("Motor %d: unexpected error %v", r.ID, r.Err)
+		}
+	}
+
+	written := mock.GetWritten()
+	expectedParams := []byte{0x00, 2, 1, 36, 1, 2, 43}
+	if !bytes.Equal(written[5:len(written)-1], expectedParams) {
+		t.Errorf("BulkRead params mismatch: got %X, want %X", written[5:len(written)-1], expectedParams)
+	}
+}
+
+func TestDriverProtocol1Unsupported(t *testing.T) {
+	mock := NewMockSerialPort()
+	driver := NewDriverWithProtocol(mock, Protocol1)
+
+	if _, err := driver.SyncRead(36, 2, []uint8{1}); err == nil {
+		t.Error("Expected SyncRead to be unsupported in protocol 1.0")
+	}
+	if err := driver.BulkWrite([]BulkWriteData{{ID: 1, Addr: 30, Data: []byte{0}}}); err == nil {
+		t.Error("Expected BulkWrite to be unsupported in protocol 1.0")
+	}
+	if len(mock.GetWritten()) != 0 {
+		t.Error("Unsupported instructions must not be transmitted")
+	}
+}
+
+func TestDriverRegWriteAndAction(t *testing.T) {
+	mock := NewScriptedSerialPort(buildStatusPacket(1, 0, nil))
+	driver := NewDriver(mock)
+
+	if err := driver.RegWrite(1, 116, []byte{0x00, 0x08, 0x00, 0x00}); err != nil {
+		t.Fatalf("RegWrite failed: %v", err)
+	}
+	written := mock.GetWritten()
+	if written[7] != InstRegWrite {
+		t.Errorf("Expected RegWrite instruction, got %02X", written[7])
+	}
+
+	// Broadcast Action expects no status packet
+	if err := driver.Action(BroadcastID); err != nil {
+		t.Fatalf("Action failed: %v", err)
+	}
+	action := mock.GetWritten()[len(written):]
+	if action[4] != BroadcastID || action[7] != InstAction {
+		t.Errorf("Action packet mismatch: %X", action)
+	}
+}
+
+func TestDriverRegisteredInstruction(t *testing.T) {
+	mock := NewMockSerialPort()
+	driver := NewDriver(mock)
+
+	mock.SetResponse(buildStatusPacket(1, 0, []byte{1}))
+	staged, err := driver.RegisteredInstruction(1, 69)
+	if err != nil {
+		t.Fatalf("RegisteredInstruction failed: %v", err)
+	}
+	if !staged {
+		t.Error("Expected staged instruction")
+	}
+}
+
+func TestDriverBroadcastWrite(t *testing.T) {
+	mock := NewMockSerialPort()
+	driver := NewDriver(mock)
+
+	// No response is queued: a broadcast write must not wait for one
+	if err := driver.Write(BroadcastID, 64, []byte{0}); err != nil {
+		t.Errorf("Broadcast write failed: %v", err)
+	}
+}
+
+func TestDriverReboot(t *testing.T) {
+	mock := NewMockSerialPort()
+	driver := NewDriver(mock)
+
+	mock.SetResponse(buildStatusPacket(1, 0, nil))
+	if err := driver.Reboot(1); err != nil {
+		t.Fatalf("Reboot failed: %v", err)
+	}
+	if written := mock.GetWritten(); written[7] != InstReboot {
+		t.Errorf("Expected Reboot instruction, got %02X", written[7])
+	}
+}
+
+func TestDriverFactoryReset(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    FactoryResetMode
+		wantErr bool
+	}{
+		{"all", ResetAll, false},
+		{"except ID", ResetExceptID, false},
+		{"except ID and baud", ResetExceptIDAndBaud, false},
+		{"invalid mode", FactoryResetMode(0x05), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := NewMockSerialPort()
+			driver := NewDriver(mock)
+			mock.SetResponse(buildStatusPacket(1, 0, nil))
+
+			err := driver.FactoryReset(1, tt.mode)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("Expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FactoryReset failed: %v", err)
+			}
+			written := mock.GetWritten()
+			if written[7] != InstFactoryReset || written[8] != byte(tt.mode) {
+				t.Errorf("Packet mismatch: %X", written)
+			}
+		})
+	}
+}
+
+func TestDriverClearMultiTurn(t *testing.T) {
+	mock := NewMockSerialPort()
+	driver := NewDriver(mock)
+
+	mock.SetResponse(buildStatusPacket(1, 0, nil))
+	if err := driver.ClearMultiTurn(1); err != nil {
+		t.Fatalf("ClearMultiTurn failed: %v", err)
+	}
+	written := mock.GetWritten()
+	expected := []byte{InstClear, 0x01, 0x44, 0x58, 0x4C, 0x22}
+	if !bytes.Equal(written[7:len(written)-2], expected) {
+		t.Errorf("Clear packet mismatch: got %X, want %X", written[7:len(written)-2], expected)
+	}
+}
@@ -0,0 +1,172 @@
+package dxl
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrTimeout is returned (wrapped) when no complete packet arrives in time
+var ErrTimeout = errors.New("read timeout")
+
+// ErrMotorOffline is set on the feedback of a motor the controller marked
+// offline after repeated timeouts; it is not read until it answers a probe
+var ErrMotorOffline = errors.New("motor offline")
+
+// ErrEStopped is returned by Start when EStop was called before it finished;
+// the motors are left with torque disabled
+var ErrEStopped = errors.New("emergency stop")
+
+// StatusErrorCode is the instruction error reported in a status packet
+// (bits 0-6 of the Protocol 2.0 Error field).
+type StatusErrorCode uint8
+
+const (
+	ErrCodeNone        StatusErrorCode = 0x00
+	ErrCodeResultFail  StatusErrorCode = 0x01 // Failed to process the instruction
+	ErrCodeInstruction StatusErrorCode = 0x02 // Undefined instruction, or Action without Reg Write
+	ErrCodeCRC         StatusErrorCode = 0x03 // CRC (Protocol 1.0: checksum) mismatch
+	ErrCodeDataRange   StatusErrorCode = 0x04 // Data out of the control table range
+	ErrCodeDataLength  StatusErrorCode = 0x05 // Data shorter than the item length
+	ErrCodeDataLimit   StatusErrorCode = 0x06 // Data outside the item's limit values
+	ErrCodeAccess      StatusErrorCode = 0x07 // Write to read-only / EEPROM with torque on, or read of write-only
+)
+
+// alertBit is set in the Protocol 2.0 Error field when the device has a hardware error
+const alertBit = 0x80
+
+func (c StatusErrorCode) String() string {
+	switch c {
+	case ErrCodeNone:
+		return "none"
+	case ErrCodeResultFail:
+		return "result fail"
+	case ErrCodeInstruction:
+		return "instruction error"
+	case ErrCodeCRC:
+		return "CRC error"
+	case ErrCodeDataRange:
+		return "data range error"
+	case ErrCodeDataLength:
+		return "data length error"
+	case ErrCodeDataLimit:
+		return "data limit error"
+	case ErrCodeAccess:
+		return "access error"
+	}
+	return fmt.Sprintf("unknown error 0x%02X", uint8(c))
+}
+
+// StatusError is returned when a motor answers with a non-zero Error field.
+// Use errors.As to inspect it:
+//
+//	var se *dxl.StatusError
+//	if errors.As(err, &se) && se.Code == dxl.ErrCodeDataRange { ... }
+//
+// Alert means the device latched a hardware error; the instruction itself may
+// still have succeeded (Code == ErrCodeNone). Read the Hardware Error Status
+// register (Driver.ReadHardwareError) for the cause.
+type StatusError struct {
+	ID    uint8
+	Code  StatusErrorCode
+	Alert bool
+	Raw   uint8 // Error field as received
+}
+
+func (e *StatusError) Error() string {
+	switch {
+	case e.Code != ErrCodeNone && e.Alert:
+		return fmt.Sprintf("motor %d: %s (hardware alert)", e.ID, e.Code)
+	case e.Alert:
+		return fmt.Sprintf("motor %d: hardware alert", e.ID)
+	}
+	return fmt.Sprintf("motor %d: %s", e.ID, e.Code)
+}
+
+// isAlertOnly reports whether err is a StatusError carrying only the hardware
+// alert flag, i.e. the instruction itself succeeded.
+func isAlertOnly(err error) bool {
+	var se *StatusError
+	return errors.As(err, &se) && se.Code == ErrCodeNone
+}
+
+// decodeStatus2 converts a Protocol 2.0 Error field into a StatusError (nil if clear)
+func decodeStatus2(id, errCode uint8) error {
+	if errCode == 0 {
+		return nil
+	}
+	return &StatusError{
+		ID:    id,
+		Code:  StatusErrorCode(errCode &^ alertBit),
+		Alert: errCode&alertBit != 0,
+		Raw:   errCode,
+	}
+}
+
+// decodeStatus1 converts a Protocol 1.0 Error bitfield into a StatusError (nil if clear).
+// Protocol 1.0 reports hardware conditions (voltage, overheating, overload) in the
+// same byte; they are mapped to Alert.
+func decodeStatus1(id, errCode uint8) error {
+	if errCode == 0 {
+		return nil
+	}
+	e := &StatusError{ID: id, Raw: errCode}
+	switch {
+	case errCode&P1ErrInstruction != 0:
+		e.Code = ErrCodeInstruction
+	case errCode&P1ErrChecksum != 0:
+		e.Code = ErrCodeCRC
+	case errCode&P1ErrRange != 0:
+		e.Code = ErrCodeDataRange
+	case errCode&P1ErrAngleLimit != 0:
+		e.Code = ErrCodeDataLimit
+	}
+	e.Alert = errCode&(P1ErrInputVoltage|P1ErrOverheating|P1ErrOverload) != 0
+	return e
+}
+
+// HardwareError is the decoded Hardware Error Status register (X-series address 70).
+// Several conditions can be latched at once; test them with Has.
+type HardwareError uint8
+
+const (
+	HwErrInputVoltage    HardwareError = 1 << 0 // Input voltage out of the operating range
+	HwErrOverheating     HardwareError = 1 << 2 // Internal temperature above the limit
+	HwErrEncoder         HardwareError = 1 << 3 // Motor encoder malfunction
+	HwErrElectricalShock HardwareError = 1 << 4 // Electrical shock on the circuit / insufficient power
+	HwErrOverload        HardwareError = 1 << 5 // Persistent load beyond the maximum output
+)
+
+var hardwareErrorNames = []struct {
+	flag HardwareError
+	name string
+}{
+	{HwErrInputVoltage, "input voltage"},
+	{HwErrOverheating, "overheating"},
+	{HwErrEncoder, "motor encoder"},
+	{HwErrElectricalShock, "electrical shock"},
+	{HwErrOverload, "overload"},
+}
+
+// Has reports whether all bits of flag are set
+func (h HardwareError) Has(flag HardwareError) bool {
+	return h&flag == flag
+}
+
+func (h HardwareError) String() string {
+	if h == 0 {
+		return "none"
+	}
+	var names []string
+	known := HardwareError(0)
+	for _, n := range hardwareErrorNames {
+		known |= n.flag
+		if h.Has(n.flag) {
+			names = append(names, n.name)
+		}
+	}
+	if rest := h &^ known; rest != 0 {
+		names = append(names, fmt.Sprintf("unknown 0x%02X", uint8(rest)))
+	}
+	return strings.Join(names, "|")
+}
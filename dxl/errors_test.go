@@ -0,0 +1,129 @@
+package dxl
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDecodeStatus2(t *testing.T) {
+	tests := []struct {
+		name      string
+		errCode   uint8
+		wantCode  StatusErrorCode
+		wantAlert bool
+	}{
+		{"data range", 0x04, ErrCodeDataRange, false},
+		{"access", 0x07, ErrCodeAccess, false},
+		{"alert only", 0x80, ErrCodeNone, true},
+		{"crc with alert", 0x83, ErrCodeCRC, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var se *StatusError
+			if !errors.As(decodeStatus2(3, tt.errCode), &se) {
+				t.Fatal("Expected *StatusError")
+			}
+			if se.ID != 3 || se.Code != tt.wantCode || se.Alert != tt.wantAlert || se.Raw != tt.errCode {
+				t.Errorf("Got %+v", se)
+			}
+		})
+	}
+
+	if decodeStatus2(1, 0) != nil {
+		t.Error("Zero error field should decode to nil")
+	}
+}
+
+func TestDecodeStatus1(t *testing.T) {
+	var se *StatusError
+	if !errors.As(decodeStatus1(1, P1ErrRange|P1ErrOverheating), &se) {
+		t.Fatal("Expected *StatusError")
+	}
+	if se.Code != ErrCodeDataRange || !se.Alert {
+		t.Errorf("Got %+v, want data range with alert", se)
+	}
+
+	if !errors.As(decodeStatus1(1, P1ErrOverload), &se) || se.Code != ErrCodeNone || !se.Alert {
+		t.Errorf("Overload should be alert-only, got %+v", se)
+	}
+}
+
+func TestDriverWriteStatusError(t *testing.T) {
+	mock := NewMockSerialPort()
+	driver := NewDriver(mock)
+
+	mock.SetResponse(buildStatusPacket(1, byte(ErrCodeDataLimit), nil))
+
+	err := driver.Write4Byte(1, 116, 0xFFFFFFFF)
+	var se *StatusError
+	if !errors.As(err, &se) {
+		t.Fatalf("Expected *StatusError, got %v", err)
+	}
+	if se.Code != ErrCodeDataLimit {
+		t.Errorf("Code: got %v, want %v", se.Code, ErrCodeDataLimit)
+	}
+}
+
+func TestDriverReadAlertKeepsData(t *testing.T) {
+	mock := NewMockSerialPort()
+	driver := NewDriver(mock)
+
+	mock.SetResponse(buildStatusPacket(1, alertBit, []byte{0x00, 0x08, 0x00, 0x00}))
+
+	data, err := driver.Read(1, 132, 4)
+	var se *StatusError
+	if !errors.As(err, &se) || !se.Alert {
+		t.Fatalf("Expected alert StatusError, got %v", err)
+	}
+	if len(data) != 4 {
+		t.Errorf("Data should be returned with alert-only status, got %X", data)
+	}
+}
+
+func TestSyncRead4ByteWrapsStatusError(t *testing.T) {
+	mock := NewMockSerialPort()
+	driver := NewDriver(mock)
+
+	mock.SetResponse(buildStatusPacket(1, byte(ErrCodeAccess), nil))
+
+	_, err := driver.SyncRead4Byte(132, []uint8{1})
+	var se *StatusError
+	if !errors.As(err, &se) || se.Code != ErrCodeAccess {
+		t.Errorf("Expected wrapped access error, got %v", err)
+	}
+}
+
+func TestReadHardwareError(t *testing.T) {
+	mock := NewMockSerialPort()
+	driver := NewDriver(mock)
+
+	// Alert flag set in status, overheating + overload latched
+	mock.SetResponse(buildStatusPacket(1, alertBit, []byte{byte(HwErrOverheating | HwErrOverload)}))
+
+	hw, err := driver.ReadHardwareError(1, 70)
+	if err != nil {
+		t.Fatalf("ReadHardwareError failed: %v", err)
+	}
+	if !hw.Has(HwErrOverheating) || !hw.Has(HwErrOverload) || hw.Has(HwErrEncoder) {
+		t.Errorf("Decoded flags wrong: %v", hw)
+	}
+}
+
+func TestHardwareErrorString(t *testing.T) {
+	tests := []struct {
+		hw   HardwareError
+		want string
+	}{
+		{0, "none"},
+		{HwErrInputVoltage, "input voltage"},
+		{HwErrOverheating | HwErrOverload, "overheating|overload"},
+		{HwErrEncoder | 0x40, "motor encoder|unknown 0x40"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.hw.String(); got != tt.want {
+			t.Errorf("HardwareError(%02X).String() = %q, want %q", uint8(tt.hw), got, tt.want)
+		}
+	}
+}
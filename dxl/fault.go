@@ -0,0 +1,435 @@
+package dxl
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ControllerState is the lifecycle state of a Controller
+type ControllerState int32
+
+const (
+	StateIdle     ControllerState = iota // Not started, or stopped
+	StateStarting                        // Start is configuring the motors
+	StateRunning                         // Control loop running, goals are written
+	StateFaulted                         // A fault reaction fired: goals are dropped until Reset
+	StateEStopped                        // EStop: torque off, goals are dropped until Reset
+	StateStopping                        // Stop is shutting the loop down
+)
+
+func (s ControllerState) String() string {
+	switch s {
+	case StateIdle:
+		return "idle"
+	case StateStarting:
+		return "starting"
+	case StateRunning:
+		return "running"
+	case StateFaulted:
+		return "faulted"
+	case StateEStopped:
+		return "e-stopped"
+	case StateStopping:
+		return "stopping"
+	}
+	return fmt.Sprintf("ControllerState(%d)", int32(s))
+}
+
+// StateChange reports a controller state transition
+type StateChange struct {
+	Time  time.Time
+	From  ControllerState
+	To    ControllerState
+	Cause error // What triggered it (fault, e-stop, start failure); nil for normal transitions
+}
+
+// FaultClass groups the errors the control loop reacts to
+type FaultClass int
+
+const (
+	FaultCommLoss    FaultClass = iota // Motor timed out CommLossThreshold reads in a row and went offline
+	FaultOverheating                   // Hardware error: overheating
+	FaultOverload                      // Hardware error: overload
+	FaultVoltage                       // Hardware error: input voltage or electrical shock
+	FaultHardware                      // Other hardware errors (encoder, unknown bits)
+	FaultWatchdog                      // Bus Watchdog tripped (already re-armed when reported)
+)
+
+func (f FaultClass) String() string {
+	switch f {
+	case FaultCommLoss:
+		return "communication loss"
+	case FaultOverheating:
+		return "overheating"
+	case FaultOverload:
+		return "overload"
+	case FaultVoltage:
+		return "voltage"
+	case FaultHardware:
+		return "hardware"
+	case FaultWatchdog:
+		return "bus watchdog"
+	}
+	return fmt.Sprintf("FaultClass(%d)", int(f))
+}
+
+// FaultReaction is what the controller does when a fault class is detected
+type FaultReaction int
+
+const (
+	ReactionIgnore    FaultReaction = iota // Do nothing
+	ReactionReport                         // Emit a FaultEvent, keep running
+	ReactionHold                           // Enter StateFaulted: motors hold their last goal
+	ReactionTorqueOff                      // Enter StateFaulted and disable the faulty motor's torque
+	ReactionEStop                          // EStop every motor
+)
+
+func (r FaultReaction) String() string {
+	switch r {
+	case ReactionIgnore:
+		return "ignore"
+	case ReactionReport:
+		return "report"
+	case ReactionHold:
+		return "hold"
+	case ReactionTorqueOff:
+		return "torque-off"
+	case ReactionEStop:
+		return "e-stop"
+	}
+	return fmt.Sprintf("FaultReaction(%d)", int(r))
+}
+
+// DefaultFaultReactions hold on communication loss and disable the motor on
+// hardware errors, which the motor's own Shutdown setting usually does too.
+// A tripped bus watchdog is only reported: the loop re-arms it.
+var DefaultFaultReactions = map[FaultClass]FaultReaction{
+	FaultCommLoss:    ReactionHold,
+	FaultOverheating: ReactionTorqueOff,
+	FaultOverload:    ReactionTorqueOff,
+	FaultVoltage:     ReactionTorqueOff,
+	FaultHardware:    ReactionTorqueOff,
+	FaultWatchdog:    ReactionReport,
+}
+
+// DefaultCommLossThreshold is how many read timeouts in a row take a motor
+// offline (communication loss)
+const DefaultCommLossThreshold = 5
+
+// FaultEvent reports a detected fault and the reaction taken
+type FaultEvent struct {
+	Time     time.Time
+	ID       uint8
+	Class    FaultClass
+	Hardware HardwareError // Hardware Error Status for hardware classes
+	Err      error         // Last read error for FaultCommLoss
+	Reaction FaultReaction
+}
+
+func (e FaultEvent) String() string {
+	if e.Class == FaultCommLoss {
+		return fmt.Sprintf("motor %d: %s (%v) -> %s", e.ID, e.Class, e.Err, e.Reaction)
+	}
+	return fmt.Sprintf("motor %d: %s (%v) -> %s", e.ID, e.Class, e.Hardware, e.Reaction)
+}
+
+// faultClasses splits a Hardware Error Status into fault classes
+func faultClasses(hw HardwareError) []FaultClass {
+	var classes []FaultClass
+	if hw.Has(HwErrOverheating) {
+		classes = append(classes, FaultOverheating)
+	}
+	if hw.Has(HwErrOverload) {
+		classes = append(classes, FaultOverload)
+	}
+	if hw.Has(HwErrInputVoltage) || hw.Has(HwErrElectricalShock) {
+		classes = append(classes, FaultVoltage)
+	}
+	if hw&^(HwErrOverheating|HwErrOverload|HwErrInputVoltage|HwErrElectricalShock) != 0 {
+		classes = append(classes, FaultHardware)
+	}
+	return classes
+}
+
+// State returns the controller state (lock-free)
+func (c *Controller) State() ControllerState {
+	return ControllerState(c.state.Load())
+}
+
+// setState moves to a new state if the current one is in from (any state if
+// from is empty). Returns the previous state and whether it changed.
+func (c *Controller) setState(to ControllerState, cause error, from ...ControllerState) (ControllerState, bool) {
+	c.stateMu.Lock()
+	prev := c.State()
+	allowed := len(from) == 0
+	for _, s := range from {
+		if s == prev {
+			allowed = true
+		}
+	}
+	if !allowed || prev == to {
+		c.stateMu.Unlock()
+		return prev, false
+	}
+	c.state.Store(int32(to))
+	// Sent under stateMu so events keep the order of the transitions
+	if c.StateEvents != nil {
+		sendDropOldest(c.StateEvents, StateChange{Time: time.Now(), From: prev, To: to, Cause: cause})
+	}
+	c.stateMu.Unlock()
+	return prev, true
+}
+
+// acceptsGoals reports whether the control loop may write goals
+func (c *Controller) acceptsGoals() bool {
+	return c.State() == StateRunning
+}
+
+// SetFaultReaction sets how the control loop reacts to a fault class.
+// Thread-safe: can be called while control loop is running
+func (c *Controller) SetFaultReaction(class FaultClass, reaction FaultReaction) error {
+	if class < FaultCommLoss || class > FaultWatchdog {
+		return fmt.Errorf("unknown fault class %v", class)
+	}
+	if reaction < ReactionIgnore || reaction > ReactionEStop {
+		return fmt.Errorf("unknown fault reaction %v", reaction)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.faultReactions == nil {
+		c.faultReactions = make(map[FaultClass]FaultReaction)
+	}
+	c.faultReactions[class] = reaction
+	return nil
+}
+
+// FaultReactionFor returns the reaction configured for a fault class
+func (c *Controller) FaultReactionFor(class FaultClass) FaultReaction {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if r, ok := c.faultReactions[class]; ok {
+		return r
+	}
+	return DefaultFaultReactions[class]
+}
+
+// SetCommLossThreshold sets how many read timeouts in a row take a motor
+// offline (default DefaultCommLossThreshold). Offline motors are left out of
+// feedback reads (ErrMotorOffline) and probed until they answer again.
+func (c *Controller) SetCommLossThreshold(n int) error {
+	if n < 1 {
+		return fmt.Errorf("communication loss threshold must be at least 1, got %d", n)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.commLossThreshold = n
+	return nil
+}
+
+func (c *Controller) getCommLossThreshold() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.commLossThreshold == 0 {
+		return DefaultCommLossThreshold
+	}
+	return c.commLossThreshold
+}
+
+// checkFaults looks at one cycle of feedback for communication loss and new
+// hardware errors and runs the configured reactions. Control loop only.
+func (c *Controller) checkFaults(feedbacks []Feedback) {
+	threshold := c.getCommLossThreshold()
+	c.faultMu.Lock()
+	defer c.faultMu.Unlock()
+	if c.commFailures == nil {
+		c.resetFaultTracking()
+	}
+	for _, fb := range feedbacks {
+		if fb.Error != nil && !isAlertOnly(fb.Error) {
+			// Only timeouts mean the link is gone; CRC errors and the like do not
+			if errors.Is(fb.Error, ErrTimeout) && !c.offline[fb.ID] {
+				c.commFailures[fb.ID]++
+				if c.commFailures[fb.ID] >= threshold {
+					c.offline[fb.ID] = true
+					c.lastProbe[fb.ID] = fb.Timestamp
+					c.react(FaultEvent{ID: fb.ID, Class: FaultCommLoss, Err: fb.Error})
+				}
+			}
+			continue
+		}
+		c.commFailures[fb.ID] = 0
+		c.lastSeen[fb.ID] = fb.Timestamp
+
+		var hw HardwareError
+		switch {
+		case fb.State.Has(FieldHardwareError):
+			hw = fb.State.HardwareError
+		case fb.Error != nil:
+			// Alert flag without the register polled: read it once per alert
+			if _, known := c.hwErrors[fb.ID]; known {
+				continue
+			}
+			var err error
+			if hw, err = c.ReadHardwareError(fb.ID); err != nil && !isAlertOnly(err) {
+				continue
+			}
+		}
+		prev := c.hwErrors[fb.ID]
+		if hw == 0 {
+			delete(c.hwErrors, fb.ID)
+			continue
+		}
+		c.hwErrors[fb.ID] = hw
+		if newBits := hw &^ prev; newBits != 0 {
+			for _, class := range faultClasses(newBits) {
+				c.react(FaultEvent{ID: fb.ID, Class: class, Hardware: hw})
+			}
+		}
+	}
+}
+
+// react runs the reaction for a fault and reports it on FaultEvents
+func (c *Controller) react(ev FaultEvent) {
+	ev.Reaction = c.FaultReactionFor(ev.Class)
+	if ev.Reaction == ReactionIgnore {
+		return
+	}
+	if c.FaultEvents != nil {
+		c.eventMu.Lock()
+		ev.Time = time.Now()
+		sendDropOldest(c.FaultEvents, ev)
+		c.eventMu.Unlock()
+	}
+
+	cause := errors.New(ev.String())
+	switch ev.Reaction {
+	case ReactionHold:
+		c.setState(StateFaulted, cause, StateRunning)
+	case ReactionTorqueOff:
+		c.setState(StateFaulted, cause, StateRunning)
+		if err := c.disableTorque(ev.ID); err != nil {
+			fmt.Printf("Fault reaction: could not disable torque for ID %d: %v\n", ev.ID, err)
+		}
+	case ReactionEStop:
+		if err := c.estop(cause); err != nil {
+			fmt.Printf("Fault reaction: e-stop failed: %v\n", err)
+		}
+	}
+}
+
+// EStop disables torque on every motor in one bus transaction: a broadcast
+// Torque Enable write when all motors share its address, otherwise a single
+// Bulk Write. Pending goals are discarded and the controller stays in
+// StateEStopped, dropping goals, until Reset. Called during Start, it makes
+// Start fail with ErrEStopped once torque is off.
+// Thread-safe: can be called from any goroutine, including while the control
+// loop is mid-transaction (EStop follows it on the bus).
+func (c *Controller) EStop() error {
+	return c.estop(errors.New("e-stop requested"))
+}
+
+func (c *Controller) estop(cause error) error {
+	switch c.State() {
+	case StateIdle, StateStopping:
+		return fmt.Errorf("controller is %v", c.State())
+	}
+	c.setState(StateEStopped, cause)
+	c.commands.take(time.Now())
+	return c.torqueOffAll()
+}
+
+// torqueOffAll writes Torque Enable = 0 to all motors in one packet if the
+// protocol allows
+func (c *Controller) torqueOffAll() error {
+	ids := c.getMotorIDs()
+	addrs := make(map[uint16][]uint8)
+	var order []uint16
+	for _, id := range ids {
+		addr := c.ModelFor(id).AddrTorqueEnable
+		if _, ok := addrs[addr]; !ok {
+			order = append(order, addr)
+		}
+		addrs[addr] = append(addrs[addr], id)
+	}
+
+	c.busMu.Lock()
+	defer c.busMu.Unlock()
+
+	if c.driver == nil {
+		return nil // Start has not opened the port: nothing is enabled
+	}
+	if err := c.checkPort(); err != nil {
+		return err
+	}
+	if len(order) == 1 {
+		return c.driver.Write(BroadcastID, order[0], []byte{0})
+	}
+	if c.driver.Protocol().supports(InstBulkWrite) {
+		entries := make([]BulkWriteData, 0, len(ids))
+		for _, addr := range order {
+			for _, id := range addrs[addr] {
+				entries = append(entries, BulkWriteData{ID: id, Addr: addr, Data: []byte{0}})
+			}
+		}
+		return c.driver.BulkWrite(entries)
+	}
+	// No Bulk Write (Protocol 1.0): one broadcast per address
+	var firstErr error
+	for _, addr := range order {
+		if err := c.driver.Write(BroadcastID, addr, []byte{0}); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Reset re-arms the controller after a fault or e-stop. Motors with a latched
+// hardware error are rebooted (RecoverMotor); the others hold their present
+// position (zero goal outside position modes) and get torque back. Safety faults and pending goals are cleared.
+// The controller returns to StateRunning only if every motor was re-armed.
+func (c *Controller) Reset() error {
+	if st := c.State(); st != StateFaulted && st != StateEStopped {
+		return fmt.Errorf("nothing to reset: controller is %v", st)
+	}
+	for _, id := range c.getMotorIDs() {
+		hw, err := c.recoverMotor(id)
+		if err != nil {
+			return fmt.Errorf("reset motor %d: %w", id, err)
+		}
+		if hw == 0 {
+			// No reboot needed; RecoverMotor did nothing
+			if err := c.holdGoal(id); err != nil {
+				return fmt.Errorf("reset motor %d: %w", id, err)
+			}
+			if err := c.enableTorque(id); err != nil {
+				return fmt.Errorf("reset motor %d: failed to enable torque: %w", id, err)
+			}
+		}
+		c.ClearSafetyFault(id)
+	}
+	c.commands.take(time.Now())
+	c.clearFaults()
+	c.setState(StateRunning, nil, StateFaulted, StateEStopped)
+	return nil
+}
+
+// clearFaults forgets fault tracking once motors are re-armed (all answered,
+// so none is offline). The loop picks a hardware error up again if it is
+// still latched.
+func (c *Controller) clearFaults() {
+	c.faultMu.Lock()
+	defer c.faultMu.Unlock()
+	c.resetFaultTracking()
+}
+
+// resetFaultTracking empties the fault maps; faultMu must be held
+func (c *Controller) resetFaultTracking() {
+	c.commFailures = make(map[uint8]int)
+	c.hwErrors = make(map[uint8]HardwareError)
+	c.offline = make(map[uint8]bool)
+	c.lastProbe = make(map[uint8]time.Time)
+	if c.lastSeen == nil {
+		c.lastSeen = make(map[uint8]time.Time)
+	}
+}
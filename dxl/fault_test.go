@@ -0,0 +1,265 @@
+package dxl
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+func runningController(port SerialPortInterface, ids []uint8) *Controller {
+	c := newTestController(port, ids)
+	c.setState(StateRunning, nil)
+	return c
+}
+
+func TestControllerStateTransitions(t *testing.T) {
+	ctrl := NewController("test", 1000000, ModelXSeries)
+	if ctrl.State() != StateIdle {
+		t.Fatalf("initial state %v", ctrl.State())
+	}
+	if err := ctrl.EStop(); err == nil {
+		t.Error("EStop on an idle controller should fail")
+	}
+	if err := ctrl.Reset(); err == nil {
+		t.Error("Reset on an idle controller should fail")
+	}
+
+	// Start fails (no such port): back to idle, both transitions reported
+	if err := ctrl.Start(); err == nil {
+		t.Fatal("expected Start to fail without a port")
+	}
+	if ctrl.State() != StateIdle {
+		t.Errorf("state after failed Start = %v", ctrl.State())
+	}
+	if ch := <-ctrl.StateEvents; ch.From != StateIdle || ch.To != StateStarting {
+		t.Errorf("first change = %+v", ch)
+	}
+	if ch := <-ctrl.StateEvents; ch.To != StateIdle || ch.Cause == nil {
+		t.Errorf("second change = %+v, want idle with cause", ch)
+	}
+
+	// Transitions only from the listed states
+	if _, ok := ctrl.setState(StateFaulted, nil, StateRunning); ok {
+		t.Error("idle -> faulted must not be allowed from running only")
+	}
+}
+
+func TestControllerStateEventsOrdered(t *testing.T) {
+	// EStop, Reset, Start and the loop change state from different
+	// goroutines: each event must start where the previous one ended
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(8)) // Let senders interleave on one CPU too
+	ctrl := NewController("test", 1000000, ModelXSeries)
+	ctrl.StateEvents = make(chan StateChange, 100000) // No drops
+	ctrl.setState(StateRunning, nil)
+	<-ctrl.StateEvents
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 2000; i++ {
+				ctrl.setState(StateFaulted, nil, StateRunning)
+				ctrl.setState(StateRunning, nil, StateFaulted)
+			}
+		}()
+	}
+	wg.Wait()
+	close(ctrl.StateEvents)
+
+	prev := StateRunning
+	for ev := range ctrl.StateEvents {
+		if ev.From != prev {
+			t.Fatalf("event %v -> %v follows a change to %v", ev.From, ev.To, prev)
+		}
+		prev = ev.To
+	}
+	if prev != ctrl.State() {
+		t.Errorf("last event ends in %v, state is %v", prev, ctrl.State())
+	}
+}
+
+func TestControllerEStopBroadcast(t *testing.T) {
+	mock := NewScriptedSerialPort()
+	ctrl := runningController(mock, []uint8{1, 2, 3})
+	ctrl.Send(Command{ID: 1, Value: 4000})
+
+	if err := ctrl.EStop(); err != nil {
+		t.Fatal(err)
+	}
+	// One broadcast Write: Torque Enable (64) = 0
+	want := ctrl.driver.Protocol().BuildPacket(BroadcastID, InstWrite, []byte{64, 0, 0})
+	if !bytes.Equal(mock.GetWritten(), want) {
+		t.Errorf("written %X, want %X", mock.GetWritten(), want)
+	}
+	if ctrl.State() != StateEStopped {
+		t.Errorf("state = %v", ctrl.State())
+	}
+	if cmds := ctrl.drainCommands(time.Now()); len(cmds) != 0 {
+		t.Errorf("pending goals survived EStop: %+v", cmds)
+	}
+	if ctrl.acceptsGoals() {
+		t.Error("e-stopped controller accepts goals")
+	}
+}
+
+func TestControllerEStopWhileStarting(t *testing.T) {
+	// Start has not opened the port yet: EStop is recorded, nothing is sent
+	ctrl := NewController("test", 1000000, ModelXSeries)
+	ctrl.SetMotorIDs([]uint8{1, 2})
+	ctrl.setState(StateStarting, nil, StateIdle)
+
+	if err := ctrl.EStop(); err != nil {
+		t.Fatalf("EStop while starting: %v", err)
+	}
+	if ctrl.State() != StateEStopped {
+		t.Errorf("state = %v", ctrl.State())
+	}
+}
+
+func TestRecoverMotorRespectsEStop(t *testing.T) {
+	mock := NewScriptedSerialPort()
+	ctrl := runningController(mock, []uint8{1})
+	if err := ctrl.EStop(); err != nil {
+		t.Fatal(err)
+	}
+	before := len(mock.GetWritten())
+
+	if _, err := ctrl.RecoverMotor(1); !errors.Is(err, ErrEStopped) {
+		t.Errorf("RecoverMotor while e-stopped: err = %v, want ErrEStopped", err)
+	}
+	ctrl.setState(StateFaulted, nil)
+	if _, err := ctrl.RecoverMotor(1); err == nil {
+		t.Error("RecoverMotor while faulted should fail")
+	}
+	if sent := mock.GetWritten()[before:]; len(sent) != 0 {
+		t.Errorf("nothing should be sent, got %X", sent)
+	}
+}
+
+func TestControllerEStopMixedLayouts(t *testing.T) {
+	mock := NewScriptedSerialPort()
+	ctrl := runningController(mock, []uint8{1, 2})
+	ctrl.SetMotorModel(2, ModelProSeries)
+
+	if err := ctrl.EStop(); err != nil {
+		t.Fatal(err)
+	}
+	written := mock.GetWritten()
+	if written[7] != InstBulkWrite {
+		t.Fatalf("expected a single Bulk Write, got %X", written)
+	}
+	// ID 1 @64 and ID 2 @562, one byte each
+	if !bytes.Contains(written, []byte{1, 64, 0, 1, 0, 0}) || !bytes.Contains(written, []byte{2, 0x32, 0x02, 1, 0, 0}) {
+		t.Errorf("bulk entries missing: %X", written)
+	}
+}
+
+func TestControllerCommLossReaction(t *testing.T) {
+	ctrl := runningController(NewScriptedSerialPort(), []uint8{1, 2})
+	ctrl.SetCommLossThreshold(3)
+	lost := fmt.Errorf("motor 1: %w", ErrTimeout)
+
+	for i := 0; i < 2; i++ {
+		ctrl.checkFaults([]Feedback{{ID: 1, Error: lost}, {ID: 2}})
+	}
+	if ctrl.State() != StateRunning {
+		t.Fatal("faulted before the threshold")
+	}
+	ctrl.checkFaults([]Feedback{{ID: 1, Error: lost}, {ID: 2}})
+	if ctrl.State() != StateFaulted {
+		t.Fatalf("state = %v, want faulted", ctrl.State())
+	}
+	ev := <-ctrl.FaultEvents
+	if ev.ID != 1 || ev.Class != FaultCommLoss || ev.Reaction != ReactionHold || ev.Err != lost {
+		t.Errorf("event = %+v", ev)
+	}
+	// Reported once, not on every further failure
+	ctrl.checkFaults([]Feedback{{ID: 1, Error: lost}})
+	if len(ctrl.FaultEvents) != 0 {
+		t.Error("communication loss reported twice")
+	}
+
+	// Non-timeout errors do not count towards the threshold
+	ctrl.checkFaults([]Feedback{{ID: 2, Error: errors.New("crc mismatch")}})
+	if ctrl.Link(2).ConsecutiveTimeouts != 0 {
+		t.Error("CRC error counted as timeout")
+	}
+}
+
+func TestControllerHardwareFaultReactions(t *testing.T) {
+	mock := NewScriptedSerialPort(buildStatusPacket(1, 0, nil)) // Torque off ack
+	ctrl := runningController(mock, []uint8{1})
+	hot := MotorState{Fields: FieldHardwareError, HardwareError: HwErrOverheating}
+
+	ctrl.checkFaults([]Feedback{{ID: 1, State: hot}})
+	if ctrl.State() != StateFaulted {
+		t.Fatalf("state = %v, want faulted", ctrl.State())
+	}
+	if ev := <-ctrl.FaultEvents; ev.Class != FaultOverheating || ev.Reaction != ReactionTorqueOff {
+		t.Errorf("event = %+v", ev)
+	}
+	want := ctrl.driver.Protocol().BuildPacket(1, InstWrite, []byte{64, 0, 0})
+	if !bytes.Equal(mock.GetWritten(), want) {
+		t.Errorf("written %X, want torque off %X", mock.GetWritten(), want)
+	}
+
+	// Same latched error again: no new reaction. A new bit (voltage) escalates.
+	ctrl.SetFaultReaction(FaultVoltage, ReactionEStop)
+	ctrl.checkFaults([]Feedback{{ID: 1, State: hot}})
+	if len(ctrl.FaultEvents) != 0 {
+		t.Error("latched error reported twice")
+	}
+	hot.HardwareError |= HwErrInputVoltage
+	ctrl.checkFaults([]Feedback{{ID: 1, State: hot}})
+	if ev := <-ctrl.FaultEvents; ev.Class != FaultVoltage || ctrl.State() != StateEStopped {
+		t.Errorf("event = %+v, state %v", ev, ctrl.State())
+	}
+}
+
+func TestControllerFaultReactionIgnore(t *testing.T) {
+	ctrl := runningController(NewScriptedSerialPort(), []uint8{1})
+	ctrl.SetFaultReaction(FaultOverload, ReactionIgnore)
+	ctrl.checkFaults([]Feedback{{ID: 1, State: MotorState{Fields: FieldHardwareError, HardwareError: HwErrOverload}}})
+	if ctrl.State() != StateRunning || len(ctrl.FaultEvents) != 0 {
+		t.Errorf("ignored fault changed state to %v", ctrl.State())
+	}
+	if err := ctrl.SetFaultReaction(FaultClass(42), ReactionHold); err == nil {
+		t.Error("expected error for unknown class")
+	}
+	if err := ctrl.SetCommLossThreshold(0); err == nil {
+		t.Error("expected error for zero threshold")
+	}
+}
+
+func TestControllerReset(t *testing.T) {
+	mock := NewScriptedSerialPort(
+		buildStatusPacket(1, 0, []byte{0}),          // Hardware Error Status: none
+		buildStatusPacket(1, 0, []byte{0, 8, 0, 0}), // Present Position 2048
+		buildStatusPacket(1, 0, nil),                // Goal Position ack
+		buildStatusPacket(1, 0, nil),                // Torque Enable ack
+		buildStatusPacket(1, 0, []byte{1}),          // Torque Enable readback
+	)
+	ctrl := runningController(mock, []uint8{1})
+	ctrl.setState(StateFaulted, nil)
+	ctrl.SetSafetyLimits(1, SafetyLimits{MaxPosition: 10, Policy: SafetyFault})
+	ctrl.groupGoals([]Command{{ID: 1, Value: 100}})
+
+	if err := ctrl.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	if ctrl.State() != StateRunning {
+		t.Errorf("state = %v, want running", ctrl.State())
+	}
+	if ctrl.SafetyFaulted(1) {
+		t.Error("safety fault not cleared by Reset")
+	}
+	// Goal Position = 2048 written before torque
+	if !bytes.Contains(mock.GetWritten(), []byte{116, 0, 0, 8, 0, 0}) {
+		t.Errorf("position not held: %X", mock.GetWritten())
+	}
+}
@@ -0,0 +1,241 @@
+package dxl
+
+import (
+	"encoding/binary"
+	"fmt"
+	"reflect"
+)
+
+// IndirectField is one control table item packed into an indirect block
+type IndirectField struct {
+	Item   ControlItem
+	Offset uint16 // Byte offset within the block
+}
+
+// IndirectMap packs arbitrary control table items into one contiguous
+// Indirect Data block, so they can be read or written with a single
+// Sync Read/Write. Each byte of an item uses one Indirect Address slot.
+type IndirectMap struct {
+	Fields   []IndirectField
+	AddrBase uint16 // Address of the first Indirect Address slot used
+	DataBase uint16 // Address of the matching Indirect Data byte
+	Length   uint16 // Block length in bytes
+
+	addresses []uint16 // Target address of each block byte
+	index     map[string]int
+}
+
+// NewIndirectMap lays out the named items starting at Indirect_Address_<start>.
+// The used slots must map to contiguous Indirect Data bytes (X-series: 1-28
+// or 29-56, not across the two banks).
+func NewIndirectMap(def *ModelDefinition, start int, names ...string) (*IndirectMap, error) {
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no items to map")
+	}
+	m := &IndirectMap{index: make(map[string]int, len(names))}
+	for _, name := range names {
+		it, ok := def.Item(name)
+		if !ok {
+			return nil, fmt.Errorf("%s has no item %q", def.Name, name)
+		}
+		if _, dup := m.index[name]; dup {
+			return nil, fmt.Errorf("item %q mapped twice", name)
+		}
+		m.index[name] = len(m.Fields)
+		m.Fields = append(m.Fields, IndirectField{Item: it, Offset: m.Length})
+		for k := uint16(0); k < uint16(it.Size); k++ {
+			m.addresses = append(m.addresses, it.Addr+k)
+		}
+		m.Length += uint16(it.Size)
+	}
+
+	// Validate the slot range: every slot must exist, accept its target and
+	// have its data byte right after the previous one
+	for i, target := range m.addresses {
+		n := start + i
+		slot, ok := def.Item(fmt.Sprintf("Indirect_Address_%d", n))
+		if !ok {
+			return nil, fmt.Errorf("%s has no Indirect_Address_%d (block needs %d slots from %d)", def.Name, n, m.Length, start)
+		}
+		data, ok := def.Item(fmt.Sprintf("Indirect_Data_%d", n))
+		if !ok {
+			return nil, fmt.Errorf("%s has no Indirect_Data_%d", def.Name, n)
+		}
+		if slot.HasRange() && (int64(target) < slot.Min || int64(target) > slot.Max) {
+			return nil, fmt.Errorf("address %d cannot be mapped indirectly (range %d-%d)", target, slot.Min, slot.Max)
+		}
+		if i == 0 {
+			m.AddrBase, m.DataBase = slot.Addr, data.Addr
+		} else if slot.Addr != m.AddrBase+uint16(2*i) || data.Addr != m.DataBase+uint16(i) {
+			return nil, fmt.Errorf("indirect slots %d-%d are not contiguous", start, start+len(m.addresses)-1)
+		}
+	}
+	return m, nil
+}
+
+// NewIndirectMapFor lays out the items named by the `dxl` tags of a struct
+// (or pointer to struct), in field order
+func NewIndirectMapFor(def *ModelDefinition, start int, v any) (*IndirectMap, error) {
+	t := reflect.TypeOf(v)
+	if t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("expected struct, got %T", v)
+	}
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		if tag := t.Field(i).Tag.Get("dxl"); tag != "" && tag != "-" {
+			names = append(names, tag)
+		}
+	}
+	return NewIndirectMap(def, start, names...)
+}
+
+// Field returns the layout of a mapped item
+func (m *IndirectMap) Field(name string) (IndirectField, bool) {
+	i, ok := m.index[name]
+	if !ok {
+		return IndirectField{}, false
+	}
+	return m.Fields[i], true
+}
+
+// addressTable returns the Indirect Address table contents (2 bytes per slot)
+func (m *IndirectMap) addressTable() []byte {
+	table := make([]byte, 2*len(m.addresses))
+	for i, a := range m.addresses {
+		binary.LittleEndian.PutUint16(table[2*i:], a)
+	}
+	return table
+}
+
+// Values decodes a block into item values, sign-extended where needed
+func (m *IndirectMap) Values(block []byte) (map[string]int64, error) {
+	if len(block) != int(m.Length) {
+		return nil, fmt.Errorf("indirect block: expected %d bytes, got %d", m.Length, len(block))
+	}
+	values := make(map[string]int64, len(m.Fields))
+	for _, f := range m.Fields {
+		v, err := decodeItem(f.Item, block[f.Offset:f.Offset+uint16(f.Item.Size)])
+		if err != nil {
+			return nil, err
+		}
+		values[f.Item.Name] = v
+	}
+	return values, nil
+}
+
+// Decode fills the `dxl`-tagged integer fields of the struct pointed to by
+// out from a block. Tags naming unmapped items are an error.
+func (m *IndirectMap) Decode(block []byte, out any) error {
+	values, err := m.Values(block)
+	if err != nil {
+		return err
+	}
+	rv := reflect.ValueOf(out)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("expected pointer to struct, got %T", out)
+	}
+	rv = rv.Elem()
+	for i := 0; i < rv.NumField(); i++ {
+		tag := rv.Type().Field(i).Tag.Get("dxl")
+		if tag == "" || tag == "-" {
+			continue
+		}
+		v, ok := values[tag]
+		if !ok {
+			return fmt.Errorf("item %q is not in the indirect block", tag)
+		}
+		field := rv.Field(i)
+		switch field.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			field.SetInt(v)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			field.SetUint(uint64(v))
+		case reflect.Bool:
+			field.SetBool(v != 0)
+		default:
+			return fmt.Errorf("field %s: unsupported type %s", rv.Type().Field(i).Name, field.Type())
+		}
+	}
+	return nil
+}
+
+// Encode builds a block from item values. Every mapped item must be given,
+// and each is checked for access and range like WriteItem.
+func (m *IndirectMap) Encode(values map[string]int64) ([]byte, error) {
+	block := make([]byte, m.Length)
+	for _, f := range m.Fields {
+		v, ok := values[f.Item.Name]
+		if !ok {
+			return nil, fmt.Errorf("no value for %s", f.Item.Name)
+		}
+		data, err := encodeItem(f.Item, v)
+		if err != nil {
+			return nil, err
+		}
+		copy(block[f.Offset:], data)
+	}
+	return block, nil
+}
+
+// ProgramIndirect writes the map's Indirect Address table to a motor.
+// X-series motors only accept this while torque is disabled.
+func (d *Driver) ProgramIndirect(id uint8, m *IndirectMap) error {
+	return d.Write(id, m.AddrBase, m.addressTable())
+}
+
+// SyncReadIndirect reads the indirect block from several motors in one
+// Sync Read. Motors that fail are left out; an error is returned only if
+// none answered.
+func (d *Driver) SyncReadIndirect(m *IndirectMap, ids []uint8) (map[uint8][]byte, error) {
+	results, err := d.SyncRead(m.DataBase, m.Length, ids)
+	if err != nil {
+		return nil, err
+	}
+	return collectBlocks(results, m.Length)
+}
+
+// FastSyncReadIndirect is SyncReadIndirect using Fast Sync Read
+func (d *Driver) FastSyncReadIndirect(m *IndirectMap, ids []uint8) (map[uint8][]byte, error) {
+	results, err := d.FastSyncRead(m.DataBase, m.Length, ids)
+	if err != nil {
+		return nil, err
+	}
+	return collectBlocks(results, m.Length)
+}
+
+// SyncWriteIndirect writes a block (see IndirectMap.Encode) to each motor
+// in one Sync Write
+func (d *Driver) SyncWriteIndirect(m *IndirectMap, blocks map[uint8][]byte) error {
+	motors := make([]SyncWriteData, 0, len(blocks))
+	for id, block := range blocks {
+		if len(block) != int(m.Length) {
+			return fmt.Errorf("motor %d: expected %d bytes, got %d", id, m.Length, len(block))
+		}
+		motors = append(motors, SyncWriteData{ID: id, Data: block})
+	}
+	return d.SyncWrite(m.DataBase, m.Length, motors)
+}
+
+// collectBlocks is collect4Byte for arbitrary block lengths
+func collectBlocks(results []SyncReadData, length uint16) (map[uint8][]byte, error) {
+	blocks := make(map[uint8][]byte)
+	var lastErr error
+	for _, r := range results {
+		if r.Err != nil && !isAlertOnly(r.Err) {
+			lastErr = fmt.Errorf("motor %d error: %w", r.ID, r.Err)
+			continue
+		}
+		if len(r.Data) != int(length) {
+			lastErr = fmt.Errorf("motor %d: invalid data length %d", r.ID, len(r.Data))
+			continue
+		}
+		blocks[r.ID] = r.Data
+	}
+	if len(blocks) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+	return blocks, nil
+}
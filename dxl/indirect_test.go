@@ -0,0 +1,124 @@
+package dxl
+
+import (
+	"bytes"
+	"testing"
+)
+
+type testStatus struct {
+	Position    int32         `dxl:"Present_Position"`
+	Velocity    int32         `dxl:"Present_Velocity"`
+	Current     int16         `dxl:"Present_Current"`
+	Temperature uint8         `dxl:"Present_Temperature"`
+	HwError     HardwareError `dxl:"Hardware_Error_Status"`
+	Note        string
+}
+
+func TestIndirectMapLayout(t *testing.T) {
+	xm, _ := LookupModel(1020)
+	m, err := NewIndirectMapFor(xm, 1, testStatus{})
+	if err != nil {
+		t.Fatalf("NewIndirectMapFor failed: %v", err)
+	}
+	if m.Length != 12 || m.AddrBase != 168 || m.DataBase != 224 {
+		t.Errorf("layout: length %d, addr %d, data %d", m.Length, m.AddrBase, m.DataBase)
+	}
+	if f, _ := m.Field("Present_Current"); f.Offset != 8 {
+		t.Errorf("Present_Current offset: got %d, want 8", f.Offset)
+	}
+
+	// First slots point at Present Position (132..135), then Present Velocity (128..)
+	table := m.addressTable()
+	want := []byte{132, 0, 133, 0, 134, 0, 135, 0, 128, 0}
+	if !bytes.Equal(table[:10], want) {
+		t.Errorf("address table: got %v", table[:10])
+	}
+
+	// Second bank starts at slot 29
+	m2, err := NewIndirectMap(xm, 29, "Present_Position")
+	if err != nil || m2.AddrBase != 578 || m2.DataBase != 634 {
+		t.Errorf("bank 2: got %+v, %v", m2, err)
+	}
+}
+
+func TestIndirectMapErrors(t *testing.T) {
+	xm, _ := LookupModel(1020)
+	cases := map[string]func() error{
+		"unknown item": func() error { _, err := NewIndirectMap(xm, 1, "Nope"); return err },
+		"duplicate":    func() error { _, err := NewIndirectMap(xm, 1, "LED", "LED"); return err },
+		"across banks": func() error { _, err := NewIndirectMap(xm, 27, "Present_Position"); return err },
+		"past end":     func() error { _, err := NewIndirectMap(xm, 55, "Present_Position"); return err },
+		"EEPROM item":  func() error { _, err := NewIndirectMap(xm, 1, "ID"); return err }, // X range is 64-661
+		"not struct":   func() error { _, err := NewIndirectMapFor(xm, 1, 5); return err },
+	}
+	for name, f := range cases {
+		if f() == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestDriverIndirectSyncRead(t *testing.T) {
+	xm, _ := LookupModel(1020)
+	m, _ := NewIndirectMapFor(xm, 1, testStatus{})
+
+	block := []byte{
+		0x00, 0x08, 0x00, 0x00, // Position 2048
+		0xF6, 0xFF, 0xFF, 0xFF, // Velocity -10
+		0x9C, 0xFF, // Current -100
+		41,                     // Temperature
+		byte(HwErrOverheating), // Hardware error
+	}
+	mock := NewScriptedSerialPort(
+		buildStatusPacket(1, 0, nil), // Program indirect table
+		append(buildStatusPacket(1, 0, block), buildStatusPacket(2, 0, block)...),
+	)
+	d := NewDriver(mock)
+	if err := d.ProgramIndirect(1, m); err != nil {
+		t.Fatalf("ProgramIndirect failed: %v", err)
+	}
+
+	blocks, err := d.SyncReadIndirect(m, []uint8{1, 2})
+	if err != nil {
+		t.Fatalf("SyncReadIndirect failed: %v", err)
+	}
+	var st testStatus
+	if err := m.Decode(blocks[2], &st); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	want := testStatus{Position: 2048, Velocity: -10, Current: -100, Temperature: 41, HwError: HwErrOverheating}
+	if st != want {
+		t.Errorf("Decode: got %+v, want %+v", st, want)
+	}
+}
+
+func TestIndirectEncode(t *testing.T) {
+	xm, _ := LookupModel(1020)
+	m, _ := NewIndirectMap(xm, 1, "Goal_Current", "Goal_Position")
+
+	block, err := m.Encode(map[string]int64{"Goal_Current": -100, "Goal_Position": 2048})
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	want := []byte{0x9C, 0xFF, 0x00, 0x08, 0x00, 0x00}
+	if !bytes.Equal(block, want) {
+		t.Errorf("Encode: got %X, want %X", block, want)
+	}
+
+	if _, err := m.Encode(map[string]int64{"Goal_Current": 5000, "Goal_Position": 0}); err == nil {
+		t.Error("Expected range error")
+	}
+	if _, err := m.Encode(map[string]int64{"Goal_Current": 0}); err == nil {
+		t.Error("Expected error for missing value")
+	}
+
+	mock := NewScriptedSerialPort()
+	d := NewDriver(mock)
+	if err := d.SyncWriteIndirect(m, map[uint8][]byte{1: block}); err != nil {
+		t.Fatalf("SyncWriteIndirect failed: %v", err)
+	}
+	// Params start with Indirect Data 1 (224) and the block length
+	if !bytes.Contains(mock.GetWritten(), []byte{224, 0, 6, 0, 1}) {
+		t.Errorf("unexpected sync write %X", mock.GetWritten())
+	}
+}
@@ -0,0 +1,358 @@
+package dxl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// StartupPolicy decides how Start brings motors under control
+type StartupPolicy int
+
+const (
+	// StartupHoldPosition writes Present Position to Goal Position (a zero
+	// goal in velocity, PWM and current modes) before enabling torque, so a
+	// stale goal register cannot make the arm jump. Default.
+	StartupHoldPosition StartupPolicy = iota
+	// StartupTorqueOn enables torque without touching the goal register
+	StartupTorqueOn
+	// StartupTorqueOff leaves torque off; enable it later (e.g. Reset after EStop)
+	StartupTorqueOff
+)
+
+func (p StartupPolicy) String() string {
+	switch p {
+	case StartupHoldPosition:
+		return "hold-position"
+	case StartupTorqueOn:
+		return "torque-on"
+	case StartupTorqueOff:
+		return "torque-off"
+	}
+	return fmt.Sprintf("StartupPolicy(%d)", int(p))
+}
+
+// ShutdownPolicy decides what the motors do when the control loop ends
+type ShutdownPolicy int
+
+const (
+	// ShutdownTorqueOff disables torque on every motor in one bus
+	// transaction. Default: no motor is left energized once the port closes.
+	ShutdownTorqueOff ShutdownPolicy = iota
+	// ShutdownHold keeps torque on and holds the present position (zero goal
+	// in velocity, PWM and current modes). The motors stay energized after
+	// the port closes.
+	ShutdownHold
+	// ShutdownPark moves to Shutdown.Park, waits for arrival, then disables
+	// torque. Skipped (torque off only) when the controller is faulted or
+	// e-stopped.
+	ShutdownPark
+)
+
+func (p ShutdownPolicy) String() string {
+	switch p {
+	case ShutdownTorqueOff:
+		return "torque-off"
+	case ShutdownHold:
+		return "hold"
+	case ShutdownPark:
+		return "park"
+	}
+	return fmt.Sprintf("ShutdownPolicy(%d)", int(p))
+}
+
+// DefaultParkTimeout bounds how long ShutdownPark waits for arrival
+const DefaultParkTimeout = 5 * time.Second
+
+// DefaultParkTolerance is the arrival window for ShutdownPark, in ticks
+const DefaultParkTolerance = 20
+
+// Shutdown configures what Stop (or cancelling the context given to
+// StartWithContext) does to the motors
+type Shutdown struct {
+	Policy ShutdownPolicy
+
+	// Park pose for ShutdownPark: goal positions of motors in a position
+	// mode (raw, or Command.SI in SI mode). Goals pass the safety envelope
+	// and must lie inside its position range.
+	Park          []Command
+	ParkTimeout   time.Duration // Default DefaultParkTimeout
+	ParkTolerance int32         // Arrival window in ticks, default DefaultParkTolerance
+}
+
+// SetStartupPolicy sets how Start enables the motors. Must be called before Start.
+func (c *Controller) SetStartupPolicy(p StartupPolicy) error {
+	if p < StartupHoldPosition || p > StartupTorqueOff {
+		return fmt.Errorf("unknown startup policy %v", p)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.startup = p
+	return nil
+}
+
+func (c *Controller) getStartupPolicy() StartupPolicy {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.startup
+}
+
+// SetShutdown sets the shutdown policy.
+// Thread-safe: can be called while control loop is running
+func (c *Controller) SetShutdown(s Shutdown) error {
+	if s.Policy < ShutdownTorqueOff || s.Policy > ShutdownPark {
+		return fmt.Errorf("unknown shutdown policy %v", s.Policy)
+	}
+	if s.Policy == ShutdownPark && len(s.Park) == 0 {
+		return fmt.Errorf("shutdown policy park needs a park pose")
+	}
+	if s.ParkTimeout < 0 || s.ParkTolerance < 0 {
+		return fmt.Errorf("park timeout and tolerance must not be negative")
+	}
+	if s.Policy == ShutdownPark {
+		for _, cmd := range s.Park {
+			if err := c.checkParkGoal(cmd); err != nil {
+				return err
+			}
+		}
+	}
+	if s.ParkTimeout == 0 {
+		s.ParkTimeout = DefaultParkTimeout
+	}
+	if s.ParkTolerance == 0 {
+		s.ParkTolerance = DefaultParkTolerance
+	}
+	s.Park = append([]Command(nil), s.Park...)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.shutdownCfg = s
+	return nil
+}
+
+// checkParkGoal rejects a park goal outside the motor's position envelope,
+// which the clamped goal could never reach
+func (c *Controller) checkParkGoal(cmd Command) error {
+	limits, ok := c.SafetyLimitsFor(cmd.ID)
+	if !ok || !limits.HasPositionRange() {
+		return nil
+	}
+	target := int64(int32(cmd.Value))
+	if c.isSIMode() {
+		target = c.siGoal(cmd.ID, c.ModelFor(cmd.ID).AddrGoalPosition, cmd.SI)
+	}
+	if target < int64(limits.MinPosition) || target > int64(limits.MaxPosition) {
+		return fmt.Errorf("park goal %d for motor %d is outside its safety limits [%d, %d]",
+			target, cmd.ID, limits.MinPosition, limits.MaxPosition)
+	}
+	return nil
+}
+
+func (c *Controller) getShutdown() Shutdown {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.shutdownCfg
+}
+
+// StartWithContext is Start with the control loop bound to ctx: cancelling
+// ctx stops the loop like Stop does, and the shutdown policy still runs.
+func (c *Controller) StartWithContext(ctx context.Context) error {
+	if st := c.State(); st != StateIdle {
+		return fmt.Errorf("cannot start: controller is %v", st)
+	}
+	c.cancel()
+	c.ctx, c.cancel = context.WithCancel(ctx)
+	return c.Start()
+}
+
+// holdGoal makes a motor stay where it is once torque is on: Present
+// Position to Goal Position in position modes, a zero goal otherwise
+func (c *Controller) holdGoal(id uint8) error {
+	m := c.ModelFor(id)
+	addr := c.goalAddrFor(id)
+	if addr == m.AddrGoalPosition {
+		return c.holdPosition(id)
+	}
+	c.busMu.Lock()
+	defer c.busMu.Unlock()
+	if err := c.checkPort(); err != nil {
+		return err
+	}
+	if err := c.driver.Write(id, addr, encodeGoal(0, c.goalSize(id, addr))); err != nil {
+		return fmt.Errorf("failed to zero goal: %w", err)
+	}
+	return nil
+}
+
+// errPortClosed is returned by bus calls that lost the race with shutdown
+var errPortClosed = errors.New("port closed")
+
+// checkPort fails once shutdown closed the port, or before Start opened it.
+// Call with busMu held.
+func (c *Controller) checkPort() error {
+	if c.driver == nil || c.portClosed {
+		return errPortClosed
+	}
+	return nil
+}
+
+// enableAndArm enables the motors, then arms the bus watchdog last: from
+// there on the loop must keep talking. An EStop while starting turns torque
+// off but can land between motors, so it is checked again once all are
+// enabled. On any failure torque is turned off again.
+func (c *Controller) enableAndArm(ids []uint8) error {
+	estopped := func() error {
+		if c.State() == StateEStopped {
+			return fmt.Errorf("start aborted: %w", ErrEStopped)
+		}
+		return nil
+	}
+	err := estopped()
+	if err == nil {
+		err = c.enableMotors(ids)
+	}
+	if err == nil {
+		err = estopped()
+	}
+	if err == nil {
+		err = c.configureWatchdog(ids)
+	}
+	if err != nil {
+		if offErr := c.torqueOffAll(); offErr != nil {
+			fmt.Printf("Warning: failed to disable torque: %v\n", offErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// enableMotors applies the startup policy to every motor at Start
+func (c *Controller) enableMotors(ids []uint8) error {
+	policy := c.getStartupPolicy()
+	if policy == StartupTorqueOff {
+		fmt.Println("Startup policy torque-off: torque left disabled")
+		return nil
+	}
+	for _, id := range ids {
+		if policy == StartupHoldPosition {
+			if err := c.holdGoal(id); err != nil {
+				return fmt.Errorf("failed to hold position for ID %d: %v", id, err)
+			}
+		}
+		if err := c.enableTorque(id); err != nil {
+			return fmt.Errorf("failed to enable torque for ID %d: %v", id, err)
+		}
+	}
+	return nil
+}
+
+// shutdown runs when the control loop exits, whether through Stop or a
+// cancelled context: it applies the shutdown policy, then closes
+// subscriptions and the port. It never looks at the (cancelled) context.
+func (c *Controller) shutdown() {
+	from, _ := c.setState(StateStopping, nil)
+	cfg := c.getShutdown()
+	ids := c.getMotorIDs()
+
+	switch cfg.Policy {
+	case ShutdownHold:
+		if from != StateEStopped {
+			for _, id := range c.onlineIDs(ids) {
+				if err := c.holdGoal(id); err != nil {
+					fmt.Printf("Shutdown: motor %d: %v\n", id, err)
+				}
+			}
+		}
+	case ShutdownPark:
+		if from == StateRunning {
+			if err := c.park(cfg); err != nil {
+				fmt.Printf("Shutdown: %v\n", err)
+			}
+		}
+		fallthrough
+	case ShutdownTorqueOff:
+		if err := c.torqueOffAll(); err != nil {
+			fmt.Printf("Shutdown: torque off failed: %v\n", err)
+		}
+	}
+
+	c.closeSubscriptions()
+	// An EStop that got past the state check may still be on the bus
+	c.busMu.Lock()
+	c.driver.port.Close()
+	c.portClosed = true
+	c.busMu.Unlock()
+	c.setState(StateIdle, nil)
+}
+
+// park drives the park pose until every motor is within tolerance or the
+// timeout expires. Goals are re-sent each round so step limits can be
+// walked through.
+func (c *Controller) park(cfg Shutdown) error {
+	c.mu.RLock()
+	polled := c.stateFields&FieldPosition != 0
+	c.mu.RUnlock()
+	if !polled {
+		return fmt.Errorf("park needs FieldPosition in the polled state fields")
+	}
+
+	var cmds []Command
+	var ids []uint8
+	for _, cmd := range cfg.Park {
+		if c.goalAddrFor(cmd.ID) != c.ModelFor(cmd.ID).AddrGoalPosition {
+			fmt.Printf("Shutdown: motor %d is not in a position mode, not parked\n", cmd.ID)
+			continue
+		}
+		cmds = append(cmds, cmd)
+		ids = append(ids, cmd.ID)
+	}
+	if len(cmds) == 0 {
+		return nil
+	}
+
+	fmt.Println("Shutdown: moving to park pose...")
+	deadline := time.Now().Add(cfg.ParkTimeout)
+	var prev map[uint8]int64
+	for {
+		// Arrival is judged against the goals the safety envelope let
+		// through; step limits move them until they settle
+		goals := make(map[uint8]int64, len(ids))
+		groups := c.groupGoals(cmds)
+		for _, g := range groups {
+			for _, id := range g.ids {
+				if g.addr == c.ModelFor(id).AddrGoalPosition {
+					goals[id] = decodeGoal(g.values[id], g.size)
+				}
+			}
+		}
+		for _, id := range ids {
+			if _, ok := goals[id]; !ok {
+				return fmt.Errorf("park pose for motor %d rejected by the safety limits", id)
+			}
+		}
+		c.writeGroups(groups)
+
+		arrived := true
+		for _, fb := range c.readFeedback(ids) {
+			if fb.Error != nil && !isAlertOnly(fb.Error) || !fb.State.Has(FieldPosition) {
+				arrived = false
+				continue
+			}
+			if l, _ := c.SafetyLimitsFor(fb.ID); l.MaxPositionStep != 0 && goals[fb.ID] != prev[fb.ID] {
+				arrived = false // Still stepping towards the pose
+			}
+			d := int64(fb.State.Position) - goals[fb.ID]
+			if d > int64(cfg.ParkTolerance) || d < -int64(cfg.ParkTolerance) {
+				arrived = false
+			}
+		}
+		if arrived {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("park pose not reached within %v", cfg.ParkTimeout)
+		}
+		prev = goals
+		time.Sleep(10 * time.Millisecond)
+	}
+}
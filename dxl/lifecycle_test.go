@@ -0,0 +1,257 @@
+package dxl
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStartupHoldPosition(t *testing.T) {
+	mock := NewScriptedSerialPort(
+		buildStatusPacket(1, 0, []byte{0xD2, 0x04, 0, 0}), // Present Position 1234
+		buildStatusPacket(1, 0, nil),                      // Goal Position ack
+		buildStatusPacket(1, 0, nil),                      // Torque Enable ack
+		buildStatusPacket(1, 0, []byte{1}),                // Torque Enable readback
+	)
+	ctrl := newTestController(mock, []uint8{1})
+	if err := ctrl.enableMotors([]uint8{1}); err != nil {
+		t.Fatal(err)
+	}
+	written := mock.GetWritten()
+	goal := bytes.Index(written, ctrl.driver.Protocol().BuildPacket(1, InstWrite, []byte{116, 0, 0xD2, 0x04, 0, 0}))
+	torque := bytes.Index(written, ctrl.driver.Protocol().BuildPacket(1, InstWrite, []byte{64, 0, 1}))
+	if goal < 0 || torque < 0 || goal > torque {
+		t.Errorf("want goal = present position before torque on, got %X", written)
+	}
+}
+
+func TestStartupTorqueOff(t *testing.T) {
+	mock := NewScriptedSerialPort()
+	ctrl := newTestController(mock, []uint8{1, 2})
+	if err := ctrl.SetStartupPolicy(StartupTorqueOff); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctrl.enableMotors([]uint8{1, 2}); err != nil {
+		t.Fatal(err)
+	}
+	if len(mock.GetWritten()) != 0 {
+		t.Errorf("torque-off startup wrote %X", mock.GetWritten())
+	}
+	if err := ctrl.SetStartupPolicy(StartupPolicy(7)); err == nil {
+		t.Error("expected error for unknown policy")
+	}
+}
+
+func TestStartupFailureTorqueOff(t *testing.T) {
+	mock := NewScriptedSerialPort(
+		buildStatusPacket(1, 0, []byte{0xD2, 0x04, 0, 0}), // Present Position 1234
+		buildStatusPacket(1, 0, nil),                      // Goal Position ack
+		buildStatusPacket(1, 0, nil),                      // Torque Enable ack
+		buildStatusPacket(1, 0, []byte{1}),                // Torque Enable readback
+		// Bus Watchdog write: no answer
+	)
+	ctrl := watchdogController(t, mock)
+	ctrl.setState(StateStarting, nil)
+	ctrl.driver.Timeout = time.Millisecond
+	ctrl.watchdog = 100 * time.Millisecond
+
+	if err := ctrl.enableAndArm([]uint8{1}); err == nil {
+		t.Fatal("expected the bus watchdog setup to fail")
+	}
+	off := ctrl.driver.Protocol().BuildPacket(BroadcastID, InstWrite, []byte{64, 0, 0})
+	if !bytes.HasSuffix(mock.GetWritten(), off) {
+		t.Errorf("torque left on after a failed start: %X", mock.GetWritten())
+	}
+}
+
+func TestStartupEStopped(t *testing.T) {
+	mock := NewScriptedSerialPort()
+	ctrl := newTestController(mock, []uint8{1})
+	ctrl.setState(StateEStopped, nil)
+
+	if err := ctrl.enableAndArm([]uint8{1}); !errors.Is(err, ErrEStopped) {
+		t.Fatalf("err = %v, want ErrEStopped", err)
+	}
+	off := ctrl.driver.Protocol().BuildPacket(BroadcastID, InstWrite, []byte{64, 0, 0})
+	if !bytes.Equal(mock.GetWritten(), off) {
+		t.Errorf("written %X, want only torque off", mock.GetWritten())
+	}
+}
+
+func TestHoldGoalVelocityMode(t *testing.T) {
+	mock := NewScriptedSerialPort(buildStatusPacket(1, 0, nil))
+	ctrl := newTestController(mock, []uint8{1})
+	ctrl.setMode(1, OpModeVelocity)
+	if err := ctrl.holdGoal(1); err != nil {
+		t.Fatal(err)
+	}
+	want := ctrl.driver.Protocol().BuildPacket(1, InstWrite, []byte{104, 0, 0, 0, 0, 0})
+	if !bytes.Equal(mock.GetWritten(), want) {
+		t.Errorf("written %X, want zero Goal Velocity %X", mock.GetWritten(), want)
+	}
+}
+
+func TestShutdownOnContextCancel(t *testing.T) {
+	mock := NewScriptedSerialPort()
+	ctrl := runningController(mock, []uint8{1, 2})
+	ctrl.driver.Timeout = time.Millisecond
+	if err := ctrl.SetShutdown(Shutdown{Policy: ShutdownTorqueOff}); err != nil {
+		t.Fatal(err)
+	}
+	parent, cancel := context.WithCancel(context.Background())
+	ctrl.ctx, ctrl.cancel = context.WithCancel(parent)
+
+	ctrl.wg.Add(1)
+	go ctrl.controlLoop()
+	time.Sleep(10 * time.Millisecond)
+	cancel() // Not Stop: the caller's context went away
+	ctrl.wg.Wait()
+
+	off := ctrl.driver.Protocol().BuildPacket(BroadcastID, InstWrite, []byte{64, 0, 0})
+	if !bytes.HasSuffix(mock.GetWritten(), off) {
+		t.Errorf("torque not disabled on cancellation: %X", mock.GetWritten())
+	}
+	if ctrl.State() != StateIdle {
+		t.Errorf("state = %v, want idle", ctrl.State())
+	}
+	mock.mu.Lock()
+	closed := mock.closed
+	mock.mu.Unlock()
+	if !closed {
+		t.Error("port left open")
+	}
+}
+
+func TestNoBusTrafficAfterShutdown(t *testing.T) {
+	mock := NewScriptedSerialPort()
+	ctrl := runningController(mock, []uint8{1})
+	ctrl.shutdown()
+	before := len(mock.GetWritten())
+
+	// An EStop or hold that raced with shutdown must not use the closed port
+	if err := ctrl.torqueOffAll(); !errors.Is(err, errPortClosed) {
+		t.Errorf("torqueOffAll after shutdown: %v", err)
+	}
+	if err := ctrl.holdGoal(1); !errors.Is(err, errPortClosed) {
+		t.Errorf("holdGoal after shutdown: %v", err)
+	}
+	if sent := mock.GetWritten()[before:]; len(sent) != 0 {
+		t.Errorf("sent %X after the port was closed", sent)
+	}
+}
+
+func TestShutdownDefaultTorqueOff(t *testing.T) {
+	mock := NewScriptedSerialPort()
+	ctrl := runningController(mock, []uint8{1, 2})
+	ctrl.shutdown()
+	off := ctrl.driver.Protocol().BuildPacket(BroadcastID, InstWrite, []byte{64, 0, 0})
+	if !bytes.Equal(mock.GetWritten(), off) {
+		t.Errorf("default shutdown wrote %X, want torque off", mock.GetWritten())
+	}
+}
+
+func TestShutdownPark(t *testing.T) {
+	mock := NewScriptedSerialPort(
+		buildStatusPacket(1, 0, nil),                      // Park goal ack
+		buildStatusPacket(1, 0, []byte{0xE8, 0x03, 0, 0}), // Present Position 1000: arrived
+	)
+	ctrl := runningController(mock, []uint8{1})
+	if err := ctrl.SetShutdown(Shutdown{Policy: ShutdownPark, Park: []Command{{ID: 1, Value: 1010}}}); err != nil {
+		t.Fatal(err)
+	}
+	ctrl.shutdown()
+
+	written := mock.GetWritten()
+	p := ctrl.driver.Protocol()
+	park := bytes.Index(written, p.BuildPacket(1, InstWrite, []byte{116, 0, 0xF2, 0x03, 0, 0}))
+	off := bytes.Index(written, p.BuildPacket(BroadcastID, InstWrite, []byte{64, 0, 0}))
+	if park < 0 || off < park {
+		t.Errorf("want park goal then torque off, got %X", written)
+	}
+
+	// Faulted: no motion, torque off only
+	mock = NewScriptedSerialPort()
+	ctrl = runningController(mock, []uint8{1})
+	ctrl.SetShutdown(Shutdown{Policy: ShutdownPark, Park: []Command{{ID: 1, Value: 1010}}})
+	ctrl.setState(StateFaulted, nil)
+	ctrl.shutdown()
+	if !bytes.Equal(mock.GetWritten(), p.BuildPacket(BroadcastID, InstWrite, []byte{64, 0, 0})) {
+		t.Errorf("faulted park wrote %X", mock.GetWritten())
+	}
+}
+
+func TestSetShutdownValidation(t *testing.T) {
+	ctrl := NewController("test", 1000000, ModelXSeries)
+	if err := ctrl.SetShutdown(Shutdown{Policy: ShutdownPark}); err == nil {
+		t.Error("expected error for park without pose")
+	}
+	if err := ctrl.SetShutdown(Shutdown{Policy: ShutdownPolicy(9)}); err == nil {
+		t.Error("expected error for unknown policy")
+	}
+	ctrl.SetShutdown(Shutdown{Policy: ShutdownPark, Park: []Command{{ID: 1}}})
+	if cfg := ctrl.getShutdown(); cfg.ParkTimeout != DefaultParkTimeout || cfg.ParkTolerance != DefaultParkTolerance {
+		t.Errorf("defaults not applied: %+v", cfg)
+	}
+	ctrl.SetSafetyLimits(1, SafetyLimits{MinPosition: 1000, MaxPosition: 3000})
+	if err := ctrl.SetShutdown(Shutdown{Policy: ShutdownPark, Park: []Command{{ID: 1, Value: 4000}}}); err == nil {
+		t.Error("expected error for a park pose outside the safety limits")
+	}
+}
+
+func TestParkStepLimited(t *testing.T) {
+	// Step limit 100 from 1000 to 1150: goals 1100 then 1150. The motor is
+	// at the first step when it is written, which is not arrival yet.
+	mock := NewScriptedSerialPort(
+		buildStatusPacket(1, 0, nil),                      // Goal 1100 ack
+		buildStatusPacket(1, 0, []byte{0x4C, 0x04, 0, 0}), // Present Position 1100
+		buildStatusPacket(1, 0, nil),                      // Goal 1150 ack
+		buildStatusPacket(1, 0, []byte{0x7E, 0x04, 0, 0}), // Present Position 1150
+		buildStatusPacket(1, 0, nil),                      // Goal 1150 ack
+		buildStatusPacket(1, 0, []byte{0x7E, 0x04, 0, 0}), // Present Position 1150: settled
+	)
+	ctrl := runningController(mock, []uint8{1})
+	ctrl.SetSafetyLimits(1, SafetyLimits{MaxPositionStep: 100})
+	ctrl.publishFeedback([]Feedback{{ID: 1, State: MotorState{Fields: FieldPosition, Position: 1000}}})
+
+	cfg := Shutdown{Policy: ShutdownPark, Park: []Command{{ID: 1, Value: 1150}}, ParkTimeout: time.Second, ParkTolerance: 5}
+	if err := ctrl.park(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(mock.GetWritten(), ctrl.driver.Protocol().BuildPacket(1, InstWrite, []byte{116, 0, 0x7E, 0x04, 0, 0})) {
+		t.Errorf("final park goal never written: %X", mock.GetWritten())
+	}
+}
+
+func TestParkClampedPose(t *testing.T) {
+	// Envelope narrowed after SetShutdown: the clamped goal counts as arrival
+	mock := NewScriptedSerialPort(
+		buildStatusPacket(1, 0, nil),                      // Goal 3000 ack
+		buildStatusPacket(1, 0, []byte{0xB8, 0x0B, 0, 0}), // Present Position 3000
+	)
+	ctrl := runningController(mock, []uint8{1})
+	ctrl.SetSafetyLimits(1, SafetyLimits{MinPosition: 0, MaxPosition: 3000})
+	cfg := Shutdown{Policy: ShutdownPark, Park: []Command{{ID: 1, Value: 4000}}, ParkTimeout: time.Minute, ParkTolerance: 5}
+	if err := ctrl.park(cfg); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParkFailsFast(t *testing.T) {
+	cfg := Shutdown{Policy: ShutdownPark, Park: []Command{{ID: 1, Value: 4000}}, ParkTimeout: time.Minute, ParkTolerance: 5}
+
+	// Pose rejected by the envelope set after SetShutdown
+	ctrl := runningController(NewScriptedSerialPort(), []uint8{1})
+	ctrl.SetSafetyLimits(1, SafetyLimits{MinPosition: 0, MaxPosition: 3000, Policy: SafetyReject})
+	if err := ctrl.park(cfg); err == nil {
+		t.Error("expected a rejected park pose to fail")
+	}
+
+	// Position not polled: arrival can never be seen
+	ctrl = runningController(NewScriptedSerialPort(), []uint8{1})
+	ctrl.SetStateFields(FieldVelocity)
+	if err := ctrl.park(cfg); err == nil {
+		t.Error("expected park to fail without FieldPosition")
+	}
+}
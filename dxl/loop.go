@@ -0,0 +1,299 @@
+package dxl
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MaxControlRate bounds SetControlRate. Above this the bus round trip of even
+// a single motor does not fit in one period.
+const MaxControlRate = 2000.0
+
+// loopSpinWindow is how long before a deadline the loop stops sleeping and
+// spins, since OS timers overshoot by tens of microseconds or more
+const loopSpinWindow = 200 * time.Microsecond
+
+// jitterSamples is how many recent cycles the jitter percentiles cover
+const jitterSamples = 2048
+
+// PeriodHistogramBounds are the upper bounds of the period histogram buckets.
+// The last bucket of LoopStats.Histogram counts everything above.
+var PeriodHistogramBounds = []time.Duration{
+	250 * time.Microsecond,
+	500 * time.Microsecond,
+	time.Millisecond,
+	2 * time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	20 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+}
+
+// HistogramBucket counts cycles whose period was at most UpperBound
+// (0 for the overflow bucket)
+type HistogramBucket struct {
+	UpperBound time.Duration
+	Count      uint64
+}
+
+// PhaseStats summarises bus time spent in one phase of the loop
+type PhaseStats struct {
+	Count uint64
+	Mean  time.Duration
+	Max   time.Duration
+}
+
+// LoopStats describes control loop timing since Start or ResetLoopStats
+type LoopStats struct {
+	TargetPeriod time.Duration // 0 when free-running
+	Cycles       uint64
+	Overruns     uint64 // Cycles that finished after their deadline
+	Missed       uint64 // Deadlines skipped because of overruns
+
+	MinPeriod  time.Duration
+	MeanPeriod time.Duration
+	MaxPeriod  time.Duration
+	Histogram  []HistogramBucket
+
+	// Jitter is |actual period - TargetPeriod| over the most recent cycles
+	// (only with a fixed rate)
+	JitterP50 time.Duration
+	JitterP90 time.Duration
+	JitterP99 time.Duration
+	JitterMax time.Duration
+
+	Write PhaseStats // Goal writes (cycles with commands only)
+	Read  PhaseStats // Feedback reads
+
+	CommandsSuperseded uint64 // Goals replaced by a newer one before being written
+	CommandsOutOfOrder uint64 // Timestamped goals dropped as older than the pending one
+	CommandsExpired    uint64 // Goals dropped as older than SetCommandMaxAge
+}
+
+func (s LoopStats) String() string {
+	return fmt.Sprintf("cycles=%d overruns=%d period min/mean/max=%v/%v/%v jitter p50/p90/p99=%v/%v/%v write=%v read=%v",
+		s.Cycles, s.Overruns, s.MinPeriod, s.MeanPeriod, s.MaxPeriod,
+		s.JitterP50, s.JitterP90, s.JitterP99, s.Write.Mean, s.Read.Mean)
+}
+
+// phaseAcc accumulates PhaseStats
+type phaseAcc struct {
+	count uint64
+	total time.Duration
+	max   time.Duration
+}
+
+func (p *phaseAcc) add(d time.Duration) {
+	p.count++
+	p.total += d
+	if d > p.max {
+		p.max = d
+	}
+}
+
+func (p phaseAcc) stats() PhaseStats {
+	s := PhaseStats{Count: p.count, Max: p.max}
+	if p.count > 0 {
+		s.Mean = p.total / time.Duration(p.count)
+	}
+	return s
+}
+
+// loopStats collects loop timing. The control loop writes, API callers read.
+type loopStats struct {
+	mu       sync.Mutex
+	target   time.Duration
+	cycles   uint64
+	overruns uint64
+	missed   uint64
+
+	periods     uint64 // Number of recorded periods (cycles - 1)
+	periodTotal time.Duration
+	minPeriod   time.Duration
+	maxPeriod   time.Duration
+	histogram   []uint64
+
+	jitter    []time.Duration // Ring buffer
+	jitterPos int
+
+	write phaseAcc
+	read  phaseAcc
+}
+
+func (s *loopStats) reset(target time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.target = target
+	s.cycles, s.overruns, s.missed = 0, 0, 0
+	s.periods, s.periodTotal, s.minPeriod, s.maxPeriod = 0, 0, 0, 0
+	s.histogram = nil
+	s.jitter, s.jitterPos = nil, 0
+	s.write, s.read = phaseAcc{}, phaseAcc{}
+}
+
+// recordPeriod records the time between the starts of two cycles
+func (s *loopStats) recordPeriod(period time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.periods == 0 || period < s.minPeriod {
+		s.minPeriod = period
+	}
+	if period > s.maxPeriod {
+		s.maxPeriod = period
+	}
+	s.periods++
+	s.periodTotal += period
+
+	if s.histogram == nil {
+		s.histogram = make([]uint64, len(PeriodHistogramBounds)+1)
+	}
+	i := sort.Search(len(PeriodHistogramBounds), func(i int) bool { return period <= PeriodHistogramBounds[i] })
+	s.histogram[i]++
+
+	if s.target > 0 {
+		j := period - s.target
+		if j < 0 {
+			j = -j
+		}
+		if len(s.jitter) < jitterSamples {
+			s.jitter = append(s.jitter, j)
+		} else {
+			s.jitter[s.jitterPos] = j
+			s.jitterPos = (s.jitterPos + 1) % jitterSamples
+		}
+	}
+}
+
+func (s *loopStats) recordCycle(write, read time.Duration, wrote bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cycles++
+	if wrote {
+		s.write.add(write)
+	}
+	s.read.add(read)
+}
+
+func (s *loopStats) recordOverrun(missed uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.overruns++
+	s.missed += missed
+}
+
+func (s *loopStats) snapshot() LoopStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := LoopStats{
+		TargetPeriod: s.target,
+		Cycles:       s.cycles,
+		Overruns:     s.overruns,
+		Missed:       s.missed,
+		MinPeriod:    s.minPeriod,
+		MaxPeriod:    s.maxPeriod,
+		Write:        s.write.stats(),
+		Read:         s.read.stats(),
+	}
+	if s.periods > 0 {
+		out.MeanPeriod = s.periodTotal / time.Duration(s.periods)
+	}
+	out.Histogram = make([]HistogramBucket, len(PeriodHistogramBounds)+1)
+	for i := range out.Histogram {
+		if i < len(PeriodHistogramBounds) {
+			out.Histogram[i].UpperBound = PeriodHistogramBounds[i]
+		}
+		if s.histogram != nil {
+			out.Histogram[i].Count = s.histogram[i]
+		}
+	}
+	if n := len(s.jitter); n > 0 {
+		sorted := make([]time.Duration, n)
+		copy(sorted, s.jitter)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+		pct := func(p float64) time.Duration { return sorted[int(p*float64(n-1))] }
+		out.JitterP50, out.JitterP90, out.JitterP99 = pct(0.50), pct(0.90), pct(0.99)
+		out.JitterMax = sorted[n-1]
+	}
+	return out
+}
+
+// SetControlRate runs the control loop at a fixed frequency (e.g. 100-1000 Hz)
+// with deadline scheduling: each cycle starts one period after the previous
+// deadline, so timing does not drift. A cycle that ends after its deadline
+// counts as an overrun and the missed deadlines are skipped. 0 (the default)
+// runs free, as fast as bus reads complete. With a bus watchdog the period
+// must be at most half its timeout. Resets the loop statistics.
+// Thread-safe: can be called while control loop is running
+func (c *Controller) SetControlRate(hz float64) error {
+	if hz < 0 || hz > MaxControlRate {
+		return fmt.Errorf("control rate %.1f Hz out of range (0-%.0f)", hz, MaxControlRate)
+	}
+	var period time.Duration
+	if hz > 0 {
+		period = time.Duration(float64(time.Second) / hz)
+	}
+	c.mu.Lock()
+	if err := checkWatchdogPeriod(period, c.watchdog); err != nil {
+		c.mu.Unlock()
+		return err
+	}
+	c.period = period
+	c.mu.Unlock()
+	c.stats.reset(period)
+	return nil
+}
+
+// getPeriod returns the control period, 0 when free-running (thread-safe)
+func (c *Controller) getPeriod() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.period
+}
+
+// LoopStats returns control loop timing statistics
+func (c *Controller) LoopStats() LoopStats {
+	st := c.stats.snapshot()
+	st.CommandsSuperseded, st.CommandsOutOfOrder, st.CommandsExpired = c.commands.counters()
+	return st
+}
+
+// ResetLoopStats clears the loop statistics, e.g. after warm-up
+func (c *Controller) ResetLoopStats() {
+	c.stats.reset(c.getPeriod())
+	c.commands.resetCounters()
+}
+
+// nextDeadline advances a deadline past now. It returns the new deadline and
+// how many deadlines were skipped (0 if the cycle finished in time).
+func nextDeadline(deadline, now time.Time, period time.Duration) (time.Time, uint64) {
+	deadline = deadline.Add(period)
+	if !now.After(deadline) {
+		return deadline, 0
+	}
+	missed := uint64(now.Sub(deadline)/period) + 1
+	return deadline.Add(time.Duration(missed) * period), missed
+}
+
+// sleepUntil waits for the deadline: sleeping for most of it, then spinning
+// for the last loopSpinWindow. Returns false if ctx was cancelled.
+func sleepUntil(ctx context.Context, deadline time.Time) bool {
+	if d := time.Until(deadline) - loopSpinWindow; d > 0 {
+		timer := time.NewTimer(d)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return false
+		case <-timer.C:
+		}
+	}
+	for time.Now().Before(deadline) {
+		if ctx.Err() != nil {
+			return false
+		}
+	}
+	return true
+}
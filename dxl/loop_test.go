@@ -0,0 +1,105 @@
+package dxl
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextDeadline(t *testing.T) {
+	base := time.Unix(0, 0)
+	period := time.Millisecond
+
+	// On time: next deadline is one period later
+	next, missed := nextDeadline(base, base.Add(600*time.Microsecond), period)
+	if !next.Equal(base.Add(period)) || missed != 0 {
+		t.Errorf("on time: got %v missed %d", next.Sub(base), missed)
+	}
+
+	// Overrun by 2.5 periods: skip to the first deadline after now
+	next, missed = nextDeadline(base, base.Add(3500*time.Microsecond), period)
+	if !next.Equal(base.Add(4*period)) || missed != 3 {
+		t.Errorf("overrun: got %v missed %d, want 4ms missed 3", next.Sub(base), missed)
+	}
+}
+
+func TestLoopStatsSnapshot(t *testing.T) {
+	var s loopStats
+	s.reset(time.Millisecond)
+	for i := 0; i < 100; i++ {
+		s.recordPeriod(time.Millisecond + time.Duration(i)*time.Microsecond)
+		s.recordCycle(100*time.Microsecond, 300*time.Microsecond, i%2 == 0)
+	}
+	s.recordOverrun(2)
+
+	st := s.snapshot()
+	if st.Cycles != 100 || st.Overruns != 1 || st.Missed != 2 {
+		t.Errorf("counts: %+v", st)
+	}
+	if st.MinPeriod != time.Millisecond || st.MaxPeriod != 1099*time.Microsecond {
+		t.Errorf("period range %v-%v", st.MinPeriod, st.MaxPeriod)
+	}
+	if st.JitterP50 != 49*time.Microsecond || st.JitterP99 != 98*time.Microsecond || st.JitterMax != 99*time.Microsecond {
+		t.Errorf("jitter p50=%v p99=%v max=%v", st.JitterP50, st.JitterP99, st.JitterMax)
+	}
+	if st.Write.Count != 50 || st.Write.Mean != 100*time.Microsecond || st.Read.Count != 100 {
+		t.Errorf("phases write=%+v read=%+v", st.Write, st.Read)
+	}
+
+	// 1ms lands in the <=1ms bucket, the rest in <=2ms
+	var total uint64
+	for _, b := range st.Histogram {
+		total += b.Count
+		if b.UpperBound == time.Millisecond && b.Count != 1 {
+			t.Errorf("1ms bucket = %d, want 1", b.Count)
+		}
+		if b.UpperBound == 2*time.Millisecond && b.Count != 99 {
+			t.Errorf("2ms bucket = %d, want 99", b.Count)
+		}
+	}
+	if total != 100 {
+		t.Errorf("histogram total %d", total)
+	}
+}
+
+func TestControllerSetControlRate(t *testing.T) {
+	ctrl := NewController("test", 1000000, ModelXSeries)
+	if err := ctrl.SetControlRate(-1); err == nil {
+		t.Error("expected error for negative rate")
+	}
+	if err := ctrl.SetControlRate(MaxControlRate + 1); err == nil {
+		t.Error("expected error above MaxControlRate")
+	}
+	if err := ctrl.SetControlRate(500); err != nil {
+		t.Fatal(err)
+	}
+	if got := ctrl.LoopStats().TargetPeriod; got != 2*time.Millisecond {
+		t.Errorf("TargetPeriod = %v, want 2ms", got)
+	}
+}
+
+func TestControllerFixedRateLoop(t *testing.T) {
+	// Reads fail fast on the empty mock, so cycles are far shorter than the period
+	ctrl := newTestController(NewMockSerialPort(), []uint8{1})
+	ctrl.FeedbackChan = make(chan []Feedback, 1000)
+	if err := ctrl.SetControlRate(200); err != nil {
+		t.Fatal(err)
+	}
+
+	ctrl.wg.Add(1)
+	go ctrl.controlLoop()
+	time.Sleep(100 * time.Millisecond)
+	ctrl.cancel()
+	ctrl.wg.Wait()
+
+	st := ctrl.LoopStats()
+	// 100ms at 5ms: about 20 cycles, not thousands
+	if st.Cycles < 10 || st.Cycles > 25 {
+		t.Errorf("Cycles = %d, want about 20", st.Cycles)
+	}
+	if st.MeanPeriod < 4*time.Millisecond || st.MeanPeriod > 7*time.Millisecond {
+		t.Errorf("MeanPeriod = %v, want about 5ms", st.MeanPeriod)
+	}
+	if st.Read.Count != st.Cycles {
+		t.Errorf("Read.Count = %d, want %d", st.Read.Count, st.Cycles)
+	}
+}
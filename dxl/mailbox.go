@@ -0,0 +1,120 @@
+package dxl
+
+import (
+	"sync"
+	"time"
+)
+
+// commandMailbox holds the newest pending goal per motor until the control
+// loop writes it. Posting never blocks: a command for a motor that already
+// has a pending goal replaces it, commands for other motors merge into the
+// same write.
+type commandMailbox struct {
+	mu         sync.Mutex
+	pending    map[uint8]Command
+	order      []uint8       // IDs in first-posted order, for deterministic writes
+	maxAge     time.Duration // Drop timestamped commands older than this, 0 = never
+	superseded uint64        // Pending goals replaced by a newer one
+	outOfOrder uint64        // Timestamped goals dropped as older than the pending one
+	expired    uint64
+}
+
+// post merges cmds into the mailbox. A timestamped command older than the
+// pending one for the same motor is out of order and dropped.
+func (m *commandMailbox) post(cmds []Command) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.pending == nil {
+		m.pending = make(map[uint8]Command)
+	}
+	for _, cmd := range cmds {
+		old, ok := m.pending[cmd.ID]
+		if !ok {
+			m.pending[cmd.ID] = cmd
+			m.order = append(m.order, cmd.ID)
+			continue
+		}
+		if !cmd.Timestamp.IsZero() && cmd.Timestamp.Before(old.Timestamp) {
+			m.outOfOrder++ // Older than what is already pending
+			continue
+		}
+		m.superseded++
+		m.pending[cmd.ID] = cmd
+	}
+}
+
+// take removes and returns all pending commands, dropping those older than
+// maxAge at now
+func (m *commandMailbox) take(now time.Time) []Command {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.order) == 0 {
+		return nil
+	}
+	cmds := make([]Command, 0, len(m.order))
+	for _, id := range m.order {
+		cmd := m.pending[id]
+		delete(m.pending, id)
+		if m.maxAge > 0 && !cmd.Timestamp.IsZero() && now.Sub(cmd.Timestamp) > m.maxAge {
+			m.expired++
+			continue
+		}
+		cmds = append(cmds, cmd)
+	}
+	m.order = m.order[:0]
+	return cmds
+}
+
+func (m *commandMailbox) setMaxAge(d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.maxAge = d
+}
+
+func (m *commandMailbox) counters() (superseded, outOfOrder, expired uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.superseded, m.outOfOrder, m.expired
+}
+
+func (m *commandMailbox) resetCounters() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.superseded, m.outOfOrder, m.expired = 0, 0, 0
+}
+
+// Send queues goals for the next control cycle without blocking. Goals for
+// different motors, from any number of callers, merge into one write; a
+// newer goal for the same motor replaces the pending one (latest wins).
+// Set Command.Timestamp to have out-of-order goals ignored and, with
+// SetCommandMaxAge, late goals dropped instead of written.
+// Thread-safe: can be called while control loop is running
+func (c *Controller) Send(cmds ...Command) {
+	c.moveCommandChan() // Keep arrival order with goals already on CommandChan
+	c.commands.post(cmds)
+}
+
+// SetCommandMaxAge drops timestamped commands that are older than d when the
+// control loop gets to write them. 0 (the default) never drops.
+// Thread-safe: can be called while control loop is running
+func (c *Controller) SetCommandMaxAge(d time.Duration) {
+	c.commands.setMaxAge(d)
+}
+
+// moveCommandChan moves everything sent on CommandChan into the mailbox
+func (c *Controller) moveCommandChan() {
+	for {
+		select {
+		case cmds := <-c.CommandChan:
+			c.commands.post(cmds)
+		default:
+			return
+		}
+	}
+}
+
+// drainCommands returns the merged goals for this cycle
+func (c *Controller) drainCommands(now time.Time) []Command {
+	c.moveCommandChan()
+	return c.commands.take(now)
+}
@@ -0,0 +1,102 @@
+package dxl
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCommandMailboxLatestWins(t *testing.T) {
+	var m commandMailbox
+	m.post([]Command{{ID: 1, Value: 100}, {ID: 2, Value: 200}})
+	m.post([]Command{{ID: 1, Value: 150}}) // Newer goal for motor 1
+	m.post([]Command{{ID: 3, Value: 300}}) // Another executor, another motor
+
+	cmds := m.take(time.Now())
+	want := []Command{{ID: 1, Value: 150}, {ID: 2, Value: 200}, {ID: 3, Value: 300}}
+	if len(cmds) != len(want) {
+		t.Fatalf("got %d commands, want %d: %+v", len(cmds), len(want), cmds)
+	}
+	for i := range want {
+		if cmds[i].ID != want[i].ID || cmds[i].Value != want[i].Value {
+			t.Errorf("cmds[%d] = %+v, want %+v", i, cmds[i], want[i])
+		}
+	}
+	if sup, _, _ := m.counters(); sup != 1 {
+		t.Errorf("superseded = %d, want 1", sup)
+	}
+	if cmds := m.take(time.Now()); len(cmds) != 0 {
+		t.Errorf("mailbox not drained: %+v", cmds)
+	}
+}
+
+func TestCommandMailboxTimestamps(t *testing.T) {
+	var m commandMailbox
+	now := time.Now()
+
+	// A late-arriving older goal does not replace the pending newer one
+	m.post([]Command{{ID: 1, Value: 200, Timestamp: now}})
+	m.post([]Command{{ID: 1, Value: 100, Timestamp: now.Add(-time.Millisecond)}})
+	cmds := m.take(now)
+	if len(cmds) != 1 || cmds[0].Value != 200 {
+		t.Errorf("out-of-order goal won: %+v", cmds)
+	}
+	if sup, late, _ := m.counters(); sup != 0 || late != 1 {
+		t.Errorf("superseded = %d, out of order = %d, want 0 and 1", sup, late)
+	}
+
+	// Expired goals are dropped, untimestamped ones never are
+	m.setMaxAge(10 * time.Millisecond)
+	m.post([]Command{
+		{ID: 1, Value: 1, Timestamp: now.Add(-50 * time.Millisecond)},
+		{ID: 2, Value: 2, Timestamp: now.Add(-5 * time.Millisecond)},
+		{ID: 3, Value: 3},
+	})
+	cmds = m.take(now)
+	if len(cmds) != 2 || cmds[0].ID != 2 || cmds[1].ID != 3 {
+		t.Errorf("expiry: got %+v, want IDs 2 and 3", cmds)
+	}
+	if _, _, exp := m.counters(); exp != 1 {
+		t.Errorf("expired = %d, want 1", exp)
+	}
+}
+
+func TestControllerSendMergesCommandChan(t *testing.T) {
+	ctrl := newTestController(NewMockSerialPort(), []uint8{1, 2})
+	ctrl.CommandChan <- []Command{{ID: 1, Value: 10}, {ID: 2, Value: 20}}
+	ctrl.Send(Command{ID: 2, Value: 25})
+
+	cmds := ctrl.drainCommands(time.Now())
+	if len(cmds) != 2 || cmds[0].Value != 10 || cmds[1].Value != 25 {
+		t.Errorf("got %+v, want ID 1=10, ID 2=25", cmds)
+	}
+	if st := ctrl.LoopStats(); st.CommandsSuperseded != 1 {
+		t.Errorf("CommandsSuperseded = %d, want 1", st.CommandsSuperseded)
+	}
+}
+
+func TestTrajectoryExecutorDoesNotBlock(t *testing.T) {
+	profile, err := NewTrapezoidalProfile(0, 100, 1000, 10000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Nobody runs the control loop: the executor must still finish on time
+	ctrl := NewController("test", 1000000, ModelXSeries)
+	done := make(chan error, 1)
+	go func() {
+		done <- NewTrajectoryExecutor(ctrl, 1).ExecuteWithContext(context.Background(), profile, 200)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("executor blocked")
+	}
+	cmds := ctrl.drainCommands(time.Now())
+	if len(cmds) != 1 || cmds[0].Value != 100 {
+		t.Errorf("pending = %+v, want final point 100", cmds)
+	}
+}
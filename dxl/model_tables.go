@@ -0,0 +1,373 @@
+package dxl
+
+import "fmt"
+
+// Built-in control tables, transcribed from the ROBOTIS e-Manual.
+// Ranges that depend on other registers (e.g. Goal Position in
+// position mode vs. extended position mode) use the widest hardware range.
+
+func ro(name string, addr uint16, size uint8, mem MemoryArea, unit string) ControlItem {
+	return ControlItem{Name: name, Addr: addr, Size: size, Access: AccessRead, Memory: mem, Unit: unit}
+}
+
+func rw(name string, addr uint16, size uint8, mem MemoryArea, unit string, min, max int64) ControlItem {
+	return ControlItem{Name: name, Addr: addr, Size: size, Access: AccessReadWrite, Memory: mem, Unit: unit, Min: min, Max: max}
+}
+
+func signed(it ControlItem) ControlItem {
+	it.Signed = true
+	return it
+}
+
+// indirectItems generates Indirect_Address_n / Indirect_Data_n pairs
+func indirectItems(first, count int, addrBase, dataBase uint16, min, max int64) []ControlItem {
+	items := make([]ControlItem, 0, 2*count)
+	for i := 0; i < count; i++ {
+		n := first + i
+		items = append(items,
+			rw(fmt.Sprintf("Indirect_Address_%d", n), addrBase+uint16(2*i), 2, RAM, "", min, max),
+			rw(fmt.Sprintf("Indirect_Data_%d", n), dataBase+uint16(i), 1, RAM, "", 0, 255))
+	}
+	return items
+}
+
+// xTableSpec captures what differs between X-series (and MX 2.0) models
+type xTableSpec struct {
+	currentLimit  int64  // 0 if the model has no current sensing (Present_Load instead)
+	currentUnit   string // Unit of Goal/Present Current
+	velocityLimit int64
+	minVoltage    int64 // Voltage limit range, 0.1 V
+	maxVoltage    int64
+	externalPorts bool // External_Port_Mode_1..3 (540-size housings)
+	startupConfig bool // Startup_Configuration and Backup_Ready (X-series only)
+}
+
+func xSeriesItems(s xTableSpec) []ControlItem {
+	items := []ControlItem{
+		ro("Model_Number", 0, 2, EEPROM, ""),
+		ro("Model_Information", 2, 4, EEPROM, ""),
+		ro("Firmware_Version", 6, 1, EEPROM, ""),
+		rw("ID", 7, 1, EEPROM, "", 0, 252),
+		rw("Baud_Rate", 8, 1, EEPROM, "", 0, 7),
+		rw("Return_Delay_Time", 9, 1, EEPROM, "2 µs", 0, 254),
+		rw("Drive_Mode", 10, 1, EEPROM, "", 0, 13),
+		rw("Operating_Mode", 11, 1, EEPROM, "", 0, 16),
+		rw("Secondary_ID", 12, 1, EEPROM, "", 0, 255),
+		rw("Protocol_Type", 13, 1, EEPROM, "", 1, 2),
+		signed(rw("Homing_Offset", 20, 4, EEPROM, "pulse", -1044479, 1044479)),
+		rw("Moving_Threshold", 24, 4, EEPROM, "0.229 rev/min", 0, 1023),
+		rw("Temperature_Limit", 31, 1, EEPROM, "°C", 0, 100),
+		rw("Max_Voltage_Limit", 32, 2, EEPROM, "0.1 V", s.minVoltage, s.maxVoltage),
+		rw("Min_Voltage_Limit", 34, 2, EEPROM, "0.1 V", s.minVoltage, s.maxVoltage),
+		rw("PWM_Limit", 36, 2, EEPROM, "0.113 %", 0, 885),
+	}
+	if s.currentLimit > 0 {
+		items = append(items, rw("Current_Limit", 38, 2, EEPROM, s.currentUnit, 0, s.currentLimit))
+	}
+	items = append(items,
+		rw("Velocity_Limit", 44, 4, EEPROM, "0.229 rev/min", 0, s.velocityLimit),
+		rw("Max_Position_Limit", 48, 4, EEPROM, "pulse", 0, 4095),
+		rw("Min_Position_Limit", 52, 4, EEPROM, "pulse", 0, 4095),
+	)
+	if s.externalPorts {
+		items = append(items,
+			rw("External_Port_Mode_1", 56, 1, EEPROM, "", 0, 3),
+			rw("External_Port_Mode_2", 57, 1, EEPROM, "", 0, 3),
+			rw("External_Port_Mode_3", 58, 1, EEPROM, "", 0, 3),
+		)
+	}
+	if s.startupConfig {
+		items = append(items, rw("Startup_Configuration", 60, 1, EEPROM, "", 0, 3))
+	}
+	items = append(items,
+		rw("Shutdown", 63, 1, EEPROM, "", 0, 255),
+
+		rw("Torque_Enable", 64, 1, RAM, "", 0, 1),
+		rw("LED", 65, 1, RAM, "", 0, 1),
+		rw("Status_Return_Level", 68, 1, RAM, "", 0, 2),
+		ro("Registered_Instruction", 69, 1, RAM, ""),
+		ro("Hardware_Error_Status", 70, 1, RAM, ""),
+		rw("Velocity_I_Gain", 76, 2, RAM, "", 0, 16383),
+		rw("Velocity_P_Gain", 78, 2, RAM, "", 0, 16383),
+		rw("Position_D_Gain", 80, 2, RAM, "", 0, 16383),
+		rw("Position_I_Gain", 82, 2, RAM, "", 0, 16383),
+		rw("Position_P_Gain", 84, 2, RAM, "", 0, 16383),
+		rw("Feedforward_2nd_Gain", 88, 2, RAM, "", 0, 16383),
+		rw("Feedforward_1st_Gain", 90, 2, RAM, "", 0, 16383),
+		// Reads back -1 once the watchdog has tripped
+		signed(rw("Bus_Watchdog", 98, 1, RAM, "20 ms", 0, 127)),
+		signed(rw("Goal_PWM", 100, 2, RAM, "0.113 %", -885, 885)),
+	)
+	if s.currentLimit > 0 {
+		items = append(items, signed(rw("Goal_Current", 102, 2, RAM, s.currentUnit, -s.currentLimit, s.currentLimit)))
+	}
+	items = append(items,
+		signed(rw("Goal_Velocity", 104, 4, RAM, "0.229 rev/min", -s.velocityLimit, s.velocityLimit)),
+		rw("Profile_Acceleration", 108, 4, RAM, "214.577 rev/min²", 0, 32767),
+		rw("Profile_Velocity", 112, 4, RAM, "0.229 rev/min", 0, 32767),
+		signed(rw("Goal_Position", 116, 4, RAM, "pulse", -1048575, 1048575)),
+		ro("Realtime_Tick", 120, 2, RAM, "ms"),
+		ro("Moving", 122, 1, RAM, ""),
+		ro("Moving_Status", 123, 1, RAM, ""),
+		signed(ro("Present_PWM", 124, 2, RAM, "0.113 %")),
+	)
+	if s.currentLimit > 0 {
+		items = append(items, signed(ro("Present_Current", 126, 2, RAM, s.currentUnit)))
+	} else {
+		items = append(items, signed(ro("Present_Load", 126, 2, RAM, "0.1 %")))
+	}
+	items = append(items,
+		signed(ro("Present_Velocity", 128, 4, RAM, "0.229 rev/min")),
+		signed(ro("Present_Position", 132, 4, RAM, "pulse")),
+		signed(ro("Velocity_Trajectory", 136, 4, RAM, "0.229 rev/min")),
+		signed(ro("Position_Trajectory", 140, 4, RAM, "pulse")),
+		ro("Present_Input_Voltage", 144, 2, RAM, "0.1 V"),
+		ro("Present_Temperature", 146, 1, RAM, "°C"),
+	)
+	if s.startupConfig {
+		items = append(items, ro("Backup_Ready", 147, 1, RAM, ""))
+	}
+	items = append(items, indirectItems(1, 28, 168, 224, 64, 661)...)
+	items = append(items, indirectItems(29, 28, 578, 634, 64, 661)...)
+	return items
+}
+
+// proTableSpec captures what differs between legacy PRO models
+type proTableSpec struct {
+	resolution int64 // Pulses per revolution; Goal Position spans ±resolution/2
+}
+
+// proSeriesItems is the legacy PRO (H54, H42, M54, M42, L54) control table.
+// Legacy PRO has no Goal PWM; Goal_Torque is the closest equivalent.
+func proSeriesItems(s proTableSpec) []ControlItem {
+	half := s.resolution / 2
+	items := []ControlItem{
+		ro("Model_Number", 0, 2, EEPROM, ""),
+		ro("Model_Information", 2, 4, EEPROM, ""),
+		ro("Firmware_Version", 6, 1, EEPROM, ""),
+		rw("ID", 7, 1, EEPROM, "", 0, 252),
+		rw("Baud_Rate", 8, 1, EEPROM, "", 0, 8),
+		rw("Return_Delay_Time", 9, 1, EEPROM, "2 µs", 0, 254),
+		rw("Operating_Mode", 11, 1, EEPROM, "", 0, 4),
+		signed(rw("Homing_Offset", 13, 4, EEPROM, "pulse", -half, half)),
+		rw("Moving_Threshold", 17, 4, EEPROM, "", 0, 2147483647),
+		rw("Temperature_Limit", 21, 1, EEPROM, "°C", 0, 100),
+		rw("Max_Voltage_Limit", 22, 2, EEPROM, "0.1 V", 150, 400),
+		rw("Min_Voltage_Limit", 24, 2, EEPROM, "0.1 V", 150, 400),
+		ro("Acceleration_Limit", 26, 4, EEPROM, ""),
+		ro("Torque_Limit", 30, 2, EEPROM, ""),
+		ro("Velocity_Limit", 32, 4, EEPROM, ""),
+		signed(rw("Max_Position_Limit", 36, 4, EEPROM, "pulse", -half, half)),
+		signed(rw("Min_Position_Limit", 40, 4, EEPROM, "pulse", -half, half)),
+		rw("External_Port_Mode_1", 44, 1, EEPROM, "", 0, 3),
+		rw("External_Port_Mode_2", 45, 1, EEPROM, "", 0, 3),
+		rw("External_Port_Mode_3", 46, 1, EEPROM, "", 0, 3),
+		rw("External_Port_Mode_4", 47, 1, EEPROM, "", 0, 3),
+		rw("Shutdown", 48, 1, EEPROM, "", 0, 255),
+	}
+	// Legacy PRO keeps its 256 indirect addresses in EEPROM at 49..560
+	for i := 0; i < 256; i++ {
+		items = append(items, rw(fmt.Sprintf("Indirect_Address_%d", i+1), 49+uint16(2*i), 2, EEPROM, "", 0, 0))
+	}
+	items = append(items,
+		rw("Torque_Enable", 562, 1, RAM, "", 0, 1),
+		rw("LED_RED", 563, 1, RAM, "", 0, 255),
+		rw("LED_GREEN", 564, 1, RAM, "", 0, 255),
+		rw("LED_BLUE", 565, 1, RAM, "", 0, 255),
+		rw("Velocity_I_Gain", 586, 2, RAM, "", 0, 32767),
+		rw("Velocity_P_Gain", 588, 2, RAM, "", 0, 32767),
+		rw("Position_P_Gain", 594, 2, RAM, "", 0, 32767),
+		signed(rw("Goal_Position", 596, 4, RAM, "pulse", -half, half)),
+		signed(rw("Goal_Velocity", 600, 4, RAM, "0.00199234 rev/min", 0, 0)),
+		signed(rw("Goal_Torque", 604, 2, RAM, "", 0, 0)),
+		rw("Goal_Acceleration", 606, 4, RAM, "", 0, 0),
+		ro("Moving", 610, 1, RAM, ""),
+		signed(ro("Present_Position", 611, 4, RAM, "pulse")),
+		signed(ro("Present_Velocity", 615, 4, RAM, "0.00199234 rev/min")),
+		signed(ro("Present_Current", 621, 2, RAM, "")),
+		ro("Present_Input_Voltage", 623, 2, RAM, "0.1 V"),
+		ro("Present_Temperature", 625, 1, RAM, "°C"),
+		rw("External_Port_Data_1", 626, 2, RAM, "", 0, 0),
+		rw("External_Port_Data_2", 628, 2, RAM, "", 0, 0),
+		rw("External_Port_Data_3", 630, 2, RAM, "", 0, 0),
+		rw("External_Port_Data_4", 632, 2, RAM, "", 0, 0),
+	)
+	for i := 0; i < 256; i++ {
+		items = append(items, rw(fmt.Sprintf("Indirect_Data_%d", i+1), 634+uint16(i), 1, RAM, "", 0, 255))
+	}
+	items = append(items,
+		ro("Registered_Instruction", 890, 1, RAM, ""),
+		rw("Status_Return_Level", 891, 1, RAM, "", 0, 2),
+		ro("Hardware_Error_Status", 892, 1, RAM, ""),
+	)
+	return items
+}
+
+// proPlusTableSpec captures what differs between PRO+ (P-series) models
+type proPlusTableSpec struct {
+	resolution int64
+}
+
+// proPlusItems is the DYNAMIXEL-P / PRO+ control table
+func proPlusItems(s proPlusTableSpec) []ControlItem {
+	half := s.resolution / 2
+	items := []ControlItem{
+		ro("Model_Number", 0, 2, EEPROM, ""),
+		ro("Model_Information", 2, 4, EEPROM, ""),
+		ro("Firmware_Version", 6, 1, EEPROM, ""),
+		rw("ID", 7, 1, EEPROM, "", 0, 252),
+		rw("Baud_Rate", 8, 1, EEPROM, "", 0, 8),
+		rw("Return_Delay_Time", 9, 1, EEPROM, "2 µs", 0, 254),
+		rw("Drive_Mode", 10, 1, EEPROM, "", 0, 13),
+		rw("Operating_Mode", 11, 1, EEPROM, "", 0, 16),
+		rw("Secondary_ID", 12, 1, EEPROM, "", 0, 255),
+		rw("Protocol_Type", 13, 1, EEPROM, "", 2, 2),
+		signed(rw("Homing_Offset", 20, 4, EEPROM, "pulse", -2147483648, 2147483647)),
+		rw("Moving_Threshold", 24, 4, EEPROM, "0.01 rev/min", 0, 10),
+		rw("Temperature_Limit", 31, 1, EEPROM, "°C", 0, 100),
+		rw("Max_Voltage_Limit", 32, 2, EEPROM, "0.1 V", 150, 350),
+		rw("Min_Voltage_Limit", 34, 2, EEPROM, "0.1 V", 150, 350),
+		rw("PWM_Limit", 36, 2, EEPROM, "", 0, 2009),
+		rw("Current_Limit", 38, 2, EEPROM, "1 mA", 0, 0),
+		rw("Acceleration_Limit", 40, 4, EEPROM, "1 rev/min²", 0, 0),
+		rw("Velocity_Limit", 44, 4, EEPROM, "0.01 rev/min", 0, 0),
+		signed(rw("Max_Position_Limit", 48, 4, EEPROM, "pulse", -half, half)),
+		signed(rw("Min_Position_Limit", 52, 4, EEPROM, "pulse", -half, half)),
+		rw("External_Port_Mode_1", 56, 1, EEPROM, "", 0, 3),
+		rw("External_Port_Mode_2", 57, 1, EEPROM, "", 0, 3),
+		rw("External_Port_Mode_3", 58, 1, EEPROM, "", 0, 3),
+		rw("External_Port_Mode_4", 59, 1, EEPROM, "", 0, 3),
+		rw("Startup_Configuration", 60, 1, EEPROM, "", 0, 3),
+		rw("Shutdown", 63, 1, EEPROM, "", 0, 255),
+	}
+	items = append(items, indirectItems(1, 128, 168, 634, 0, 0)...)
+	items = append(items,
+		rw("Torque_Enable", 512, 1, RAM, "", 0, 1),
+		rw("LED_RED", 513, 1, RAM, "", 0, 255),
+		rw("LED_GREEN", 514, 1, RAM, "", 0, 255),
+		rw("LED_BLUE", 515, 1, RAM, "", 0, 255),
+		rw("Status_Return_Level", 516, 1, RAM, "", 0, 2),
+		ro("Registered_Instruction", 517, 1, RAM, ""),
+		ro("Hardware_Error_Status", 518, 1, RAM, ""),
+		rw("Velocity_I_Gain", 524, 2, RAM, "", 0, 32767),
+		rw("Velocity_P_Gain", 526, 2, RAM, "", 0, 32767),
+		rw("Position_D_Gain", 528, 2, RAM, "", 0, 32767),
+		rw("Position_I_Gain", 530, 2, RAM, "", 0, 32767),
+		rw("Position_P_Gain", 532, 2, RAM, "", 0, 32767),
+		rw("Feedforward_2nd_Gain", 536, 2, RAM, "", 0, 32767),
+		rw("Feedforward_1st_Gain", 538, 2, RAM, "", 0, 32767),
+		signed(rw("Bus_Watchdog", 546, 1, RAM, "20 ms", 0, 127)),
+		signed(rw("Goal_PWM", 548, 2, RAM, "", -2009, 2009)),
+		signed(rw("Goal_Current", 550, 2, RAM, "1 mA", 0, 0)),
+		signed(rw("Goal_Velocity", 552, 4, RAM, "0.01 rev/min", 0, 0)),
+		rw("Profile_Acceleration", 556, 4, RAM, "1 rev/min²", 0, 0),
+		rw("Profile_Velocity", 560, 4, RAM, "0.01 rev/min", 0, 0),
+		signed(rw("Goal_Position", 564, 4, RAM, "pulse", -half, half)),
+		ro("Realtime_Tick", 568, 2, RAM, "ms"),
+		ro("Moving", 570, 1, RAM, ""),
+		ro("Moving_Status", 571, 1, RAM, ""),
+		signed(ro("Present_PWM", 572, 2, RAM, "")),
+		signed(ro("Present_Current", 574, 2, RAM, "1 mA")),
+		signed(ro("Present_Velocity", 576, 4, RAM, "0.01 rev/min")),
+		signed(ro("Present_Position", 580, 4, RAM, "pulse")),
+		signed(ro("Velocity_Trajectory", 584, 4, RAM, "0.01 rev/min")),
+		signed(ro("Position_Trajectory", 588, 4, RAM, "pulse")),
+		ro("Present_Input_Voltage", 592, 2, RAM, "0.1 V"),
+		ro("Present_Temperature", 594, 1, RAM, "°C"),
+		rw("External_Port_Data_1", 600, 2, RAM, "", 0, 0),
+		rw("External_Port_Data_2", 602, 2, RAM, "", 0, 0),
+		rw("External_Port_Data_3", 604, 2, RAM, "", 0, 0),
+		rw("External_Port_Data_4", 606, 2, RAM, "", 0, 0),
+	)
+	return items
+}
+
+// positionTypeInfo returns the ROBOTIS [type info] keys for a motor whose
+// position range [min, max] spans one revolution around zero
+func positionTypeInfo(zero, min, max int64) map[string]float64 {
+	return map[string]float64{
+		"value_of_zero_radian_position": float64(zero),
+		"value_of_max_radian_position":  float64(max),
+		"value_of_min_radian_position":  float64(min),
+		"min_radian":                    -3.14159265,
+		"max_radian":                    3.14159265,
+	}
+}
+
+func init() {
+	xl330 := xTableSpec{currentLimit: 1750, currentUnit: "1 mA", velocityLimit: 2047, minVoltage: 31, maxVoltage: 70, startupConfig: true}
+	xl430 := xTableSpec{velocityLimit: 1023, minVoltage: 60, maxVoltage: 140, startupConfig: true}
+	xm430 := xTableSpec{currentLimit: 1193, currentUnit: "2.69 mA", velocityLimit: 1023, minVoltage: 95, maxVoltage: 160, startupConfig: true}
+	xh430w := xTableSpec{currentLimit: 648, currentUnit: "2.69 mA", velocityLimit: 1023, minVoltage: 95, maxVoltage: 160, startupConfig: true}
+	xh430v := xTableSpec{currentLimit: 689, currentUnit: "1.34 mA", velocityLimit: 1023, minVoltage: 95, maxVoltage: 160, startupConfig: true}
+	x540 := xTableSpec{currentLimit: 2047, currentUnit: "2.69 mA", velocityLimit: 1023, minVoltage: 95, maxVoltage: 160, externalPorts: true, startupConfig: true}
+	xw430 := xTableSpec{currentLimit: 1193, currentUnit: "2.69 mA", velocityLimit: 1023, minVoltage: 95, maxVoltage: 160, startupConfig: true}
+	mx28 := xTableSpec{velocityLimit: 1023, minVoltage: 95, maxVoltage: 160}
+	mx64 := xTableSpec{currentLimit: 1941, currentUnit: "3.36 mA", velocityLimit: 1023, minVoltage: 95, maxVoltage: 160}
+	mx106 := xTableSpec{currentLimit: 2047, currentUnit: "3.36 mA", velocityLimit: 1023, minVoltage: 95, maxVoltage: 160}
+
+	builtin := []struct {
+		number uint16
+		name   string
+		series string
+		items  []ControlItem
+	}{
+		{1190, "XL330-M077", "X", xSeriesItems(xl330)},
+		{1200, "XL330-M288", "X", xSeriesItems(xl330)},
+		{1060, "XL430-W250", "X", xSeriesItems(xl430)},
+		{1090, "2XL430-W250", "X", xSeriesItems(xl430)},
+		{1070, "XC430-W150", "X", xSeriesItems(xl430)},
+		{1080, "XC430-W240", "X", xSeriesItems(xl430)},
+		{1030, "XM430-W210", "X", xSeriesItems(xm430)},
+		{1020, "XM430-W350", "X", xSeriesItems(xm430)},
+		{1010, "XH430-W210", "X", xSeriesItems(xh430w)},
+		{1000, "XH430-W350", "X", xSeriesItems(xh430w)},
+		{1050, "XH430-V210", "X", xSeriesItems(xh430v)},
+		{1040, "XH430-V350", "X", xSeriesItems(xh430v)},
+		{1130, "XM540-W150", "X", xSeriesItems(x540)},
+		{1120, "XM540-W270", "X", xSeriesItems(x540)},
+		{1110, "XH540-W150", "X", xSeriesItems(x540)},
+		{1100, "XH540-W270", "X", xSeriesItems(x540)},
+		{1150, "XH540-V150", "X", xSeriesItems(x540)},
+		{1140, "XH540-V270", "X", xSeriesItems(x540)},
+		{1180, "XW540-T140", "X", xSeriesItems(x540)},
+		{1170, "XW540-T260", "X", xSeriesItems(x540)},
+		{1280, "XW430-T200", "X", xSeriesItems(xw430)},
+		{1270, "XW430-T333", "X", xSeriesItems(xw430)},
+
+		{30, "MX-28(2.0)", "MX", xSeriesItems(mx28)},
+		{311, "MX-64(2.0)", "MX", xSeriesItems(mx64)},
+		{321, "MX-106(2.0)", "MX", xSeriesItems(mx106)},
+
+		{54024, "H54-200-S500-R", "PRO", proSeriesItems(proTableSpec{resolution: 501923})},
+		{53768, "H54-100-S500-R", "PRO", proSeriesItems(proTableSpec{resolution: 501923})},
+		{51200, "H42-20-S300-R", "PRO", proSeriesItems(proTableSpec{resolution: 303750})},
+		{46352, "M54-60-S250-R", "PRO", proSeriesItems(proTableSpec{resolution: 251417})},
+		{46096, "M54-40-S250-R", "PRO", proSeriesItems(proTableSpec{resolution: 251417})},
+		{43288, "M42-10-S260-R", "PRO", proSeriesItems(proTableSpec{resolution: 263187})},
+		{38176, "L54-50-S500-R", "PRO", proSeriesItems(proTableSpec{resolution: 361384})},
+		{37928, "L54-30-S500-R", "PRO", proSeriesItems(proTableSpec{resolution: 361384})},
+		{38152, "L54-50-S290-R", "PRO", proSeriesItems(proTableSpec{resolution: 207692})},
+		{37896, "L54-30-S400-R", "PRO", proSeriesItems(proTableSpec{resolution: 288395})},
+
+		{2020, "H54P-200-S500-R", "PRO+", proPlusItems(proPlusTableSpec{resolution: 1003846})},
+		{2010, "H54P-100-S500-R", "PRO+", proPlusItems(proPlusTableSpec{resolution: 1003846})},
+		{2000, "H42P-020-S300-R", "PRO+", proPlusItems(proPlusTableSpec{resolution: 607500})},
+		{2120, "M54P-060-S250-R", "PRO+", proPlusItems(proPlusTableSpec{resolution: 502834})},
+		{2110, "M54P-040-S250-R", "PRO+", proPlusItems(proPlusTableSpec{resolution: 502834})},
+		{2100, "M42P-010-S260-R", "PRO+", proPlusItems(proPlusTableSpec{resolution: 526374})},
+	}
+	for _, b := range builtin {
+		def := &ModelDefinition{Number: b.number, Name: b.name, Series: b.series, Items: b.items}
+		if gp, ok := def.Item("Max_Position_Limit"); ok {
+			if def.Series == "PRO" || def.Series == "PRO+" {
+				def.TypeInfo = positionTypeInfo(0, gp.Min, gp.Max)
+			} else {
+				def.TypeInfo = positionTypeInfo(2048, 0, 4095)
+			}
+		}
+		if err := RegisterModel(def); err != nil {
+			panic(err)
+		}
+	}
+}
@@ -0,0 +1,265 @@
+package dxl
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// === ROBOTIS .model files ===
+//
+// The .model format (dynamixel_hardware_interface / dynamixel_workbench) is
+// a tab-separated text file with up to three sections:
+//
+//	[type info]
+//	name	value
+//	value_of_zero_radian_position	2048
+//
+//	[unit info]
+//	Data Name	value	unit	Sign Type
+//	Present Velocity	0.0239691227	rad/s	signed
+//
+//	[control table]
+//	Address	Size	Data Name
+//	132	4	Present Position
+//
+// The format does not carry access mode, memory area or ranges. Items before
+// Torque Enable are treated as EEPROM, read-only items are recognised by name,
+// and no ranges are set.
+
+type unitInfo struct {
+	scale  float64
+	unit   string
+	signed bool
+}
+
+// ParseModelFile reads a control table in ROBOTIS .model format.
+// Data names are converted to registry form ("Present Position" becomes
+// "Present_Position").
+func ParseModelFile(r io.Reader, number uint16, name string) (*ModelDefinition, error) {
+	def := &ModelDefinition{Number: number, Name: name}
+	units := make(map[string]unitInfo)
+
+	section := ""
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.ToLower(strings.TrimSpace(line[1 : len(line)-1]))
+			continue
+		}
+		fields := splitModelLine(line)
+		if isModelHeader(fields) {
+			continue
+		}
+
+		switch section {
+		case "control table":
+			if len(fields) < 3 {
+				return nil, fmt.Errorf("line %d: expected address, size and name", lineNo)
+			}
+			addr, err := strconv.ParseUint(fields[0], 10, 16)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid address %q", lineNo, fields[0])
+			}
+			size, err := strconv.ParseUint(fields[1], 10, 8)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid size %q", lineNo, fields[1])
+			}
+			def.Items = append(def.Items, ControlItem{
+				Name: itemName(strings.Join(fields[2:], " ")),
+				Addr: uint16(addr),
+				Size: uint8(size),
+			})
+		case "unit info":
+			if len(fields) < 4 {
+				return nil, fmt.Errorf("line %d: expected name, value, unit and sign type", lineNo)
+			}
+			n := len(fields)
+			scale, err := strconv.ParseFloat(fields[n-3], 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid unit value %q", lineNo, fields[n-3])
+			}
+			units[itemName(strings.Join(fields[:n-3], " "))] = unitInfo{
+				scale:  scale,
+				unit:   fields[n-2],
+				signed: strings.EqualFold(fields[n-1], "signed"),
+			}
+		case "type info":
+			if len(fields) != 2 {
+				return nil, fmt.Errorf("line %d: expected name and value", lineNo)
+			}
+			v, err := strconv.ParseFloat(fields[1], 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid value %q", lineNo, fields[1])
+			}
+			if def.TypeInfo == nil {
+				def.TypeInfo = make(map[string]float64)
+			}
+			def.TypeInfo[fields[0]] = v
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(def.Items) == 0 {
+		return nil, fmt.Errorf("model %s: no [control table] entries", name)
+	}
+
+	sort.SliceStable(def.Items, func(i, j int) bool { return def.Items[i].Addr < def.Items[j].Addr })
+	ramStart := uint16(0)
+	for _, it := range def.Items {
+		if it.Name == "Torque_Enable" {
+			ramStart = it.Addr
+			break
+		}
+	}
+	for i := range def.Items {
+		it := &def.Items[i]
+		it.Memory = RAM
+		if it.Addr < ramStart {
+			it.Memory = EEPROM
+		}
+		it.Access = AccessReadWrite
+		if readOnlyItem(it.Name) {
+			it.Access = AccessRead
+		}
+		if u, ok := units[it.Name]; ok {
+			it.Signed = u.signed
+			if u.unit != "raw" {
+				it.Unit = strconv.FormatFloat(u.scale, 'g', -1, 64) + " " + u.unit
+			}
+		} else {
+			it.Signed = signedItem(it.Name)
+		}
+	}
+
+	if err := def.validate(); err != nil {
+		return nil, err
+	}
+	def.buildIndex()
+	return def, nil
+}
+
+// LoadModelFile reads a .model file. The model name is the file name
+// without extension, upper-cased (xm430_w350.model -> XM430_W350).
+func LoadModelFile(path string, number uint16) (*ModelDefinition, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	name := strings.ToUpper(strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)))
+	return ParseModelFile(f, number, name)
+}
+
+// splitModelLine splits on tabs, falling back to whitespace for files whose
+// tabs were converted to spaces
+func splitModelLine(line string) []string {
+	if !strings.Contains(line, "\t") {
+		return strings.Fields(line)
+	}
+	var fields []string
+	for _, f := range strings.Split(line, "\t") {
+		if f = strings.TrimSpace(f); f != "" {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// isModelHeader recognises the column header row at the top of each section
+// by its first column: "Address", "name" or "Data Name". Items whose names
+// merely start with "Data" are not headers.
+func isModelHeader(fields []string) bool {
+	if len(fields) == 0 {
+		return false
+	}
+	first := strings.ToLower(fields[0])
+	if first == "data" && len(fields) > 1 {
+		// Whitespace-separated file: "Data Name" spans two fields
+		first += " " + strings.ToLower(fields[1])
+	}
+	return first == "address" || first == "name" || first == "data name"
+}
+
+// itemName converts a ROBOTIS data name to registry form
+func itemName(dataName string) string {
+	return strings.Join(strings.Fields(dataName), "_")
+}
+
+func readOnlyItem(name string) bool {
+	switch name {
+	case "Model_Number", "Model_Information", "Firmware_Version",
+		"Registered_Instruction", "Hardware_Error_Status", "Realtime_Tick",
+		"Moving", "Moving_Status", "Velocity_Trajectory", "Position_Trajectory",
+		"Backup_Ready":
+		return true
+	}
+	return strings.HasPrefix(name, "Present_")
+}
+
+func signedItem(name string) bool {
+	switch name {
+	case "Homing_Offset", "Bus_Watchdog", "Goal_PWM", "Goal_Current", "Goal_Velocity",
+		"Goal_Position", "Goal_Torque", "Present_PWM", "Present_Current", "Present_Load",
+		"Present_Velocity", "Present_Position", "Velocity_Trajectory", "Position_Trajectory":
+		return true
+	}
+	return false
+}
+
+// === JSON schema ===
+
+// ParseModelJSON decodes a model definition in this package's JSON schema:
+//
+//	{
+//	  "model_number": 1020,
+//	  "name": "XM430-W350",
+//	  "series": "X",
+//	  "control_table": [
+//	    {"name": "Goal_Position", "address": 116, "size": 4, "access": "RW",
+//	     "memory": "RAM", "signed": true, "unit": "pulse", "min": -1048575, "max": 1048575}
+//	  ]
+//	}
+func ParseModelJSON(data []byte) (*ModelDefinition, error) {
+	var def ModelDefinition
+	if err := json.Unmarshal(data, &def); err != nil {
+		return nil, fmt.Errorf("invalid model JSON: %v", err)
+	}
+	if err := def.validate(); err != nil {
+		return nil, err
+	}
+	def.buildIndex()
+	return &def, nil
+}
+
+// LoadModelJSON reads a model definition from a JSON file
+func LoadModelJSON(path string) (*ModelDefinition, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseModelJSON(data)
+}
+
+// WriteModelJSON saves a model definition as indented JSON, e.g. to use a
+// built-in table as the starting point for custom firmware
+func WriteModelJSON(path string, def *ModelDefinition) error {
+	data, err := json.MarshalIndent(def, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
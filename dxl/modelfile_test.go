@@ -0,0 +1,157 @@
+package dxl
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testModelFile = `[type info]
+name	value
+value_of_zero_radian_position	2048
+value_of_max_radian_position	4095
+
+[unit info]
+Data Name	value	unit	Sign Type
+Goal Position	0.001533981	rad	signed
+Present Velocity	0.0239691227	rad/s	signed
+Indirect Data Write	1.0	raw	unsigned
+
+[control table]
+Address	Size	Data Name
+0	2	Model Number
+11	1	Operating Mode
+20	4	Homing Offset
+64	1	Torque Enable
+116	4	Goal Position
+128	4	Present Velocity
+132	4	Present Position
+`
+
+func TestParseModelFile(t *testing.T) {
+	def, err := ParseModelFile(strings.NewReader(testModelFile), 1020, "XM430-W350")
+	if err != nil {
+		t.Fatalf("ParseModelFile failed: %v", err)
+	}
+	if len(def.Items) != 7 {
+		t.Fatalf("Expected 7 items, got %d", len(def.Items))
+	}
+	if def.TypeInfo["value_of_zero_radian_position"] != 2048 {
+		t.Errorf("TypeInfo: got %v", def.TypeInfo)
+	}
+
+	checks := []struct {
+		name   string
+		addr   uint16
+		access AccessMode
+		memory MemoryArea
+		signed bool
+	}{
+		{"Model_Number", 0, AccessRead, EEPROM, false},
+		{"Homing_Offset", 20, AccessReadWrite, EEPROM, true}, // signed by name, no unit info
+		{"Torque_Enable", 64, AccessReadWrite, RAM, false},
+		{"Goal_Position", 116, AccessReadWrite, RAM, true},
+		{"Present_Position", 132, AccessRead, RAM, true},
+	}
+	for _, c := range checks {
+		it, ok := def.Item(c.name)
+		if !ok {
+			t.Errorf("%s missing", c.name)
+			continue
+		}
+		if it.Addr != c.addr || it.Access != c.access || it.Memory != c.memory || it.Signed != c.signed {
+			t.Errorf("%s: got %+v", c.name, it)
+		}
+	}
+	if it, _ := def.Item("Goal_Position"); it.Unit != "0.001533981 rad" {
+		t.Errorf("Goal_Position unit: got %q", it.Unit)
+	}
+
+	// The loaded table drives the Controller like a built-in one
+	m := def.MotorModel()
+	if m.AddrGoalPosition != 116 || m.AddrPresentPosition != 132 || m.AddrTorqueEnable != 64 {
+		t.Errorf("MotorModel: got %+v", m)
+	}
+}
+
+func TestParseModelFileSpaces(t *testing.T) {
+	// Tabs converted to spaces by an editor
+	src := "[control table]\nAddress Size Data Name\n64 1 Torque Enable\n132 4 Present Position\n"
+	def, err := ParseModelFile(strings.NewReader(src), 1, "test")
+	if err != nil {
+		t.Fatalf("ParseModelFile failed: %v", err)
+	}
+	if it, ok := def.Item("Present_Position"); !ok || it.Size != 4 {
+		t.Errorf("Present_Position: got %+v, %v", it, ok)
+	}
+}
+
+func TestParseModelFileDataItems(t *testing.T) {
+	// Rows naming items that start with "Data" are not column headers
+	for _, src := range []string{
+		"[unit info]\nData Name\tvalue\tunit\tSign Type\nData Offset\t1.0\traw\tsigned\n" +
+			"[control table]\nAddress\tSize\tData Name\n64\t1\tTorque Enable\n90\t2\tData Offset\n",
+		"[unit info]\nData Name value unit Sign Type\nData Offset 1.0 raw signed\n" +
+			"[control table]\nAddress Size Data Name\n64 1 Torque Enable\n90 2 Data Offset\n",
+	} {
+		def, err := ParseModelFile(strings.NewReader(src), 1, "test")
+		if err != nil {
+			t.Fatalf("ParseModelFile failed: %v", err)
+		}
+		if it, ok := def.Item("Data_Offset"); !ok || !it.Signed {
+			t.Errorf("Data_Offset: got %+v, %v, want a signed item", it, ok)
+		}
+	}
+}
+
+func TestParseModelFileErrors(t *testing.T) {
+	cases := map[string]string{
+		"empty":        "[type info]\nname\tvalue\n",
+		"bad address":  "[control table]\nx\t1\tLED\n",
+		"bad size":     "[control table]\n65\t3\tLED\n",
+		"bad unit":     "[unit info]\nGoal Position\tabc\trad\tsigned\n[control table]\n116\t4\tGoal Position\n",
+		"missing name": "[control table]\n65\t1\n",
+	}
+	for name, src := range cases {
+		if _, err := ParseModelFile(strings.NewReader(src), 1, "test"); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestModelJSONRoundTrip(t *testing.T) {
+	builtin, _ := LookupModel(1020)
+	path := filepath.Join(t.TempDir(), "xm430.json")
+	if err := WriteModelJSON(path, builtin); err != nil {
+		t.Fatalf("WriteModelJSON failed: %v", err)
+	}
+
+	def, err := LoadModelJSON(path)
+	if err != nil {
+		t.Fatalf("LoadModelJSON failed: %v", err)
+	}
+	if def.Number != 1020 || def.Name != "XM430-W350" || len(def.Items) != len(builtin.Items) {
+		t.Fatalf("Round trip mismatch: %d %s %d items", def.Number, def.Name, len(def.Items))
+	}
+	want, _ := builtin.Item("Goal_Velocity")
+	got, _ := def.Item("Goal_Velocity")
+	if got != want {
+		t.Errorf("Goal_Velocity: got %+v, want %+v", got, want)
+	}
+}
+
+func TestParseModelJSONErrors(t *testing.T) {
+	cases := map[string]string{
+		"syntax":    `{"name": `,
+		"access":    `{"name":"m","control_table":[{"name":"LED","address":65,"size":1,"access":"W","memory":"RAM"}]}`,
+		"memory":    `{"name":"m","control_table":[{"name":"LED","address":65,"size":1,"access":"RW","memory":"ROM"}]}`,
+		"size":      `{"name":"m","control_table":[{"name":"LED","address":65,"size":8,"access":"RW","memory":"RAM"}]}`,
+		"no name":   `{"model_number":5,"control_table":[]}`,
+		"bad range": `{"name":"m","control_table":[{"name":"LED","address":65,"size":1,"access":"RW","memory":"RAM","min":5,"max":1}]}`,
+	}
+	for name, src := range cases {
+		if _, err := ParseModelJSON([]byte(src)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package dxl
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// AccessMode tells whether a control table item can be written
+type AccessMode uint8
+
+const (
+	AccessRead      AccessMode = iota + 1 // R: read-only
+	AccessReadWrite                       // RW
+)
+
+func (a AccessMode) String() string {
+	switch a {
+	case AccessRead:
+		return "R"
+	case AccessReadWrite:
+		return "RW"
+	}
+	return fmt.Sprintf("AccessMode(%d)", uint8(a))
+}
+
+// MarshalText encodes the access mode as "R" or "RW"
+func (a AccessMode) MarshalText() ([]byte, error) {
+	if a != AccessRead && a != AccessReadWrite {
+		return nil, fmt.Errorf("invalid access mode %d", uint8(a))
+	}
+	return []byte(a.String()), nil
+}
+
+// UnmarshalText decodes "R" or "RW"
+func (a *AccessMode) UnmarshalText(text []byte) error {
+	switch strings.ToUpper(string(text)) {
+	case "R":
+		*a = AccessRead
+	case "RW":
+		*a = AccessReadWrite
+	default:
+		return fmt.Errorf("invalid access mode %q", text)
+	}
+	return nil
+}
+
+// MemoryArea tells where a control table item lives.
+// EEPROM items persist across power cycles and can only be written while
+// torque is disabled.
+type MemoryArea uint8
+
+const (
+	EEPROM MemoryArea = iota + 1
+	RAM
+)
+
+func (m MemoryArea) String() string {
+	switch m {
+	case EEPROM:
+		return "EEPROM"
+	case RAM:
+		return "RAM"
+	}
+	return fmt.Sprintf("MemoryArea(%d)", uint8(m))
+}
+
+// MarshalText encodes the memory area as "EEPROM" or "RAM"
+func (m MemoryArea) MarshalText() ([]byte, error) {
+	if m != EEPROM && m != RAM {
+		return nil, fmt.Errorf("invalid memory area %d", uint8(m))
+	}
+	return []byte(m.String()), nil
+}
+
+// UnmarshalText decodes "EEPROM" or "RAM"
+func (m *MemoryArea) UnmarshalText(text []byte) error {
+	switch strings.ToUpper(string(text)) {
+	case "EEPROM":
+		*m = EEPROM
+	case "RAM":
+		*m = RAM
+	default:
+		return fmt.Errorf("invalid memory area %q", text)
+	}
+	return nil
+}
+
+// ControlItem describes one entry of a motor's control table
+type ControlItem struct {
+	Name   string     `json:"name"` // ROBOTIS data name with underscores, e.g. "Present_Position"
+	Addr   uint16     `json:"address"`
+	Size   uint8      `json:"size"` // 1, 2 or 4 bytes
+	Access AccessMode `json:"access"`
+	Memory MemoryArea `json:"memory"`
+	Signed bool       `json:"signed,omitempty"` // Two's complement value
+	Unit   string     `json:"unit,omitempty"`   // Informational, e.g. "0.229 rev/min"
+
+	// Min and Max bound writable values. Both are zero when the item has no
+	// fixed range (read-only items, or limits that depend on other registers).
+	Min int64 `json:"min,omitempty"`
+	Max int64 `json:"max,omitempty"`
+}
+
+// HasRange reports whether Min/Max are set
+func (it ControlItem) HasRange() bool {
+	return it.Min != 0 || it.Max != 0
+}
+
+// ModelDefinition is the full control table of one motor model
+type ModelDefinition struct {
+	Number uint16        `json:"model_number"` // Model Number reported by Ping
+	Name   string        `json:"name"`         // e.g. "XM430-W350"
+	Series string        `json:"series,omitempty"`
+	Items  []ControlItem `json:"control_table"`
+
+	// TypeInfo holds model-level parameters such as
+	// value_of_zero_radian_position, as found in ROBOTIS .model files
+	TypeInfo map[string]float64 `json:"type_info,omitempty"`
+
+	index map[string]int
+}
+
+// Item returns the control table item with the given name
+func (m *ModelDefinition) Item(name string) (ControlItem, bool) {
+	if m.index == nil {
+		m.buildIndex()
+	}
+	i, ok := m.index[name]
+	if !ok {
+		return ControlItem{}, false
+	}
+	return m.Items[i], true
+}
+
+func (m *ModelDefinition) buildIndex() {
+	m.index = make(map[string]int, len(m.Items))
+	for i, it := range m.Items {
+		m.index[it.Name] = i
+	}
+}
+
+// addr returns the address of an item, or 0 if the model lacks it
+func (m *ModelDefinition) addr(name string) uint16 {
+	it, ok := m.Item(name)
+	if !ok {
+		return 0
+	}
+	return it.Addr
+}
+
+// MotorModel derives the Controller address set from the control table.
+// Addresses of items the model lacks are left at 0.
+func (m *ModelDefinition) MotorModel() MotorModel {
+	return MotorModel{
+		AddrTorqueEnable:    m.addr("Torque_Enable"),
+		AddrGoalPosition:    m.addr("Goal_Position"),
+		AddrGoalVelocity:    m.addr("Goal_Velocity"),
+		AddrGoalPWM:         m.addr("Goal_PWM"),
+		AddrGoalCurrent:     m.addr("Goal_Current"),
+		AddrPresentPosition: m.addr("Present_Position"),
+		AddrOperatingMode:   m.addr("Operating_Mode"),
+
+		AddrHardwareErrorStatus:   m.addr("Hardware_Error_Status"),
+		AddrRegisteredInstruction: m.addr("Registered_Instruction"),
+	}
+}
+
+// validate checks the definition for mistakes that would corrupt reads/writes
+func (m *ModelDefinition) validate() error {
+	if m.Name == "" {
+		return fmt.Errorf("model %d: missing name", m.Number)
+	}
+	seen := make(map[string]bool, len(m.Items))
+	for _, it := range m.Items {
+		if it.Name == "" {
+			return fmt.Errorf("model %s: item at address %d has no name", m.Name, it.Addr)
+		}
+		if seen[it.Name] {
+			return fmt.Errorf("model %s: duplicate item %s", m.Name, it.Name)
+		}
+		seen[it.Name] = true
+		switch it.Size {
+		case 1, 2, 4:
+		default:
+			return fmt.Errorf("model %s: item %s has invalid size %d", m.Name, it.Name, it.Size)
+		}
+		if it.Access != AccessRead && it.Access != AccessReadWrite {
+			return fmt.Errorf("model %s: item %s has invalid access mode", m.Name, it.Name)
+		}
+		if it.Memory != EEPROM && it.Memory != RAM {
+			return fmt.Errorf("model %s: item %s has invalid memory area", m.Name, it.Name)
+		}
+		if it.Min > it.Max {
+			return fmt.Errorf("model %s: item %s has min %d > max %d", m.Name, it.Name, it.Min, it.Max)
+		}
+	}
+	return nil
+}
+
+// === Registry ===
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[uint16]*ModelDefinition)
+)
+
+// RegisterModel adds a model definition to the registry, replacing any
+// definition with the same model number.
+func RegisterModel(def *ModelDefinition) error {
+	if err := def.validate(); err != nil {
+		return err
+	}
+	def.buildIndex()
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[def.Number] = def
+	return nil
+}
+
+// LookupModel returns the definition for a model number (as returned by Ping)
+func LookupModel(number uint16) (*ModelDefinition, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	def, ok := registry[number]
+	return def, ok
+}
+
+// Models returns all registered definitions ordered by model number
+func Models() []*ModelDefinition {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	defs := make([]*ModelDefinition, 0, len(registry))
+	for _, def := range registry {
+		defs = append(defs, def)
+	}
+	sort.Slice(defs, func(i, j int) bool { return defs[i].Number < defs[j].Number })
+	return defs
+}
+
+// compatibleLayout reports whether two address sets drive motors the same
+// way, so one MotorModel can serve both.
+func compatibleLayout(a, b MotorModel) bool {
+	return a.AddrTorqueEnable == b.AddrTorqueEnable &&
+		a.AddrGoalPosition == b.AddrGoalPosition &&
+		a.AddrPresentPosition == b.AddrPresentPosition &&
+		a.AddrOperatingMode == b.AddrOperatingMode
+}
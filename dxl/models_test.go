@@ -0,0 +1,88 @@
+package dxl
+
+import "testing"
+
+func TestBuiltinModelsRegistered(t *testing.T) {
+	for _, number := range []uint16{1190, 1200, 1060, 1070, 1020, 1030, 1120, 1000, 1100, 1170, 30, 311, 321, 54024, 2020} {
+		if _, ok := LookupModel(number); !ok {
+			t.Errorf("model %d not registered", number)
+		}
+	}
+	if _, ok := LookupModel(9999); ok {
+		t.Error("unexpected definition for unknown model 9999")
+	}
+}
+
+func TestModelDefinitionMotorModel(t *testing.T) {
+	cases := []struct {
+		number uint16
+		want   MotorModel
+	}{
+		{1020, ModelXSeries},       // XM430-W350
+		{311, ModelXSeries},        // MX-64(2.0)
+		{54024, ModelProSeries},    // H54-200-S500-R
+		{2020, ModelProPlusSeries}, // H54P-200-S500-R
+	}
+	for _, tc := range cases {
+		def, _ := LookupModel(tc.number)
+		if got := def.MotorModel(); got != tc.want {
+			t.Errorf("%s: got %+v, want %+v", def.Name, got, tc.want)
+		}
+	}
+}
+
+func TestModelDefinitionItems(t *testing.T) {
+	xm, _ := LookupModel(1020)
+	it, ok := xm.Item("Goal_Current")
+	if !ok {
+		t.Fatal("XM430 should have Goal_Current")
+	}
+	if it.Addr != 102 || it.Size != 2 || !it.Signed || it.Access != AccessReadWrite || it.Memory != RAM {
+		t.Errorf("Goal_Current: got %+v", it)
+	}
+	if it.Min != -1193 || it.Max != 1193 {
+		t.Errorf("Goal_Current range: got [%d, %d]", it.Min, it.Max)
+	}
+
+	// XL430 has no current sensing and reports load instead
+	xl, _ := LookupModel(1060)
+	if _, ok := xl.Item("Goal_Current"); ok {
+		t.Error("XL430 should not have Goal_Current")
+	}
+	if it, ok := xl.Item("Present_Load"); !ok || it.Addr != 126 {
+		t.Errorf("XL430 Present_Load: got %+v, %v", it, ok)
+	}
+
+	if it, _ := xm.Item("Indirect_Data_56"); it.Addr != 661 {
+		t.Errorf("Indirect_Data_56: got address %d, want 661", it.Addr)
+	}
+	if it, _ := xm.Item("Present_Position"); it.Access != AccessRead || it.HasRange() {
+		t.Errorf("Present_Position: got %+v", it)
+	}
+}
+
+func TestBuiltinModelsNoOverlap(t *testing.T) {
+	for _, def := range Models() {
+		used := make(map[uint16]string)
+		for _, it := range def.Items {
+			for a := it.Addr; a < it.Addr+uint16(it.Size); a++ {
+				if other, ok := used[a]; ok {
+					t.Errorf("%s: %s overlaps %s at address %d", def.Name, it.Name, other, a)
+				}
+				used[a] = it.Name
+			}
+		}
+	}
+}
+
+func TestRegisterModelValidation(t *testing.T) {
+	bad := &ModelDefinition{Number: 65000, Name: "bad", Items: []ControlItem{
+		{Name: "X", Addr: 0, Size: 3, Access: AccessRead, Memory: RAM},
+	}}
+	if err := RegisterModel(bad); err == nil {
+		t.Error("expected error for invalid item size")
+	}
+	if _, ok := LookupModel(65000); ok {
+		t.Error("invalid model should not be registered")
+	}
+}
@@ -12,6 +12,10 @@ const (
 	Header3  = 0xFD
 	Reserved = 0x00
 
+	// BroadcastID addresses every motor on the bus. Devices do not answer
+	// broadcast instructions (except Ping and the read instructions).
+	BroadcastID = 0xFE
+
 	InstPing         = 0x01
 	InstRead         = 0x02
 	InstWrite        = 0x03
@@ -19,13 +23,81 @@ const (
 	InstAction       = 0x05
 	InstFactoryReset = 0x06
 	InstReboot       = 0x08
+	InstClear        = 0x10
+	InstBackup       = 0x20
 	InstStatus       = 0x55
 	InstSyncRead     = 0x82
 	InstSyncWrite    = 0x83
+	InstFastSyncRead = 0x8A
 	InstBulkRead     = 0x92
 	InstBulkWrite    = 0x93
+	InstFastBulkRead = 0x9A
 )
 
+// Protocol encodes and decodes packets for one Dynamixel protocol version.
+// A Driver is bound to a single Protocol, so ports speaking different
+// versions can be driven side by side in the same process.
+// Use the Protocol1 and Protocol2 values.
+type Protocol interface {
+	// Version returns the protocol version number (1 or 2).
+	Version() int
+
+	// BuildPacket constructs an instruction packet.
+	BuildPacket(id uint8, inst uint8, params []byte) []byte
+
+	// ParsePacket validates a status packet.
+	// Returns: ID, ErrorCode, Params, valid/error
+	ParsePacket(packet []byte) (id uint8, errCode uint8, params []byte, err error)
+
+	// frame locates the first complete packet in buf.
+	// Returns the start index (-1 if no header was found) and the end index
+	// (0 if the packet is not complete yet).
+	frame(buf []byte) (start, end int)
+
+	// wordSize is the width in bytes of address and length parameters.
+	wordSize() int
+
+	// supports reports whether the instruction exists in this protocol.
+	supports(inst uint8) bool
+
+	// statusError decodes a status packet Error field (nil if no error).
+	statusError(id, errCode uint8) error
+}
+
+// Protocol2 is the Dynamixel Protocol 2.0 codec (CRC16, byte stuffing, 2-byte addresses).
+var Protocol2 Protocol = protocol2{}
+
+type protocol2 struct{}
+
+func (protocol2) Version() int { return 2 }
+
+func (protocol2) BuildPacket(id uint8, inst uint8, params []byte) []byte {
+	return BuildPacket(id, inst, params)
+}
+
+func (protocol2) ParsePacket(packet []byte) (uint8, uint8, []byte, error) {
+	return ParsePacket(packet)
+}
+
+func (protocol2) frame(buf []byte) (int, int) {
+	start := findPacketStart(buf)
+	if start == -1 || len(buf) < start+MinHeaderSize {
+		return start, 0
+	}
+	bodyLen := uint16(buf[start+5]) | (uint16(buf[start+6]) << 8)
+	end := start + MinHeaderSize + int(bodyLen)
+	if len(buf) < end {
+		return start, 0
+	}
+	return start, end
+}
+
+func (protocol2) wordSize() int { return 2 }
+
+func (protocol2) supports(inst uint8) bool { return true }
+
+func (protocol2) statusError(id, errCode uint8) error { return decodeStatus2(id, errCode) }
+
 // CRC16 Lookup Table (CRC-16-IBM / XMODEM variant used by DXL 2.0)
 // CRC16 Lookup Table
 var crcTable [256]uint16
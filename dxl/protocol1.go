@@ -0,0 +1,124 @@
+package dxl
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Protocol 1.0 packet layout:
+//
+//	Instruction: [0xFF, 0xFF, ID, Length, Instruction, Params..., Checksum]
+//	Status:      [0xFF, 0xFF, ID, Length, Error, Params..., Checksum]
+//
+// Length counts the bytes after itself (Instruction/Error + Params + Checksum).
+// Checksum = ~(ID + Length + Instruction/Error + Params) & 0xFF.
+// There is no byte stuffing and addresses/lengths are single bytes.
+
+// Protocol 1.0 error bits (status packet Error field)
+const (
+	P1ErrInputVoltage = 1 << 0
+	P1ErrAngleLimit   = 1 << 1
+	P1ErrOverheating  = 1 << 2
+	P1ErrRange        = 1 << 3
+	P1ErrChecksum     = 1 << 4
+	P1ErrOverload     = 1 << 5
+	P1ErrInstruction  = 1 << 6
+)
+
+// p1HeaderSize is the bytes needed to know a Protocol 1.0 packet length: FF FF ID LEN
+const p1HeaderSize = 4
+
+// Protocol1 is the Dynamixel Protocol 1.0 codec used by AX/RX/EX and MX (1.0 firmware) servos.
+var Protocol1 Protocol = protocol1{}
+
+type protocol1 struct{}
+
+func (protocol1) Version() int { return 1 }
+
+// checksum1 computes the Protocol 1.0 checksum over ID, Length, Instruction and Params
+func checksum1(data []byte) byte {
+	var sum byte
+	for _, b := range data {
+		sum += b
+	}
+	return ^sum
+}
+
+// BuildPacket constructs a Protocol 1.0 Packet
+func (protocol1) BuildPacket(id uint8, inst uint8, params []byte) []byte {
+	// Length = Instruction(1) + Params(N) + Checksum(1)
+	length := 2 + len(params)
+
+	pkt := make([]byte, 0, p1HeaderSize+length)
+	pkt = append(pkt, 0xFF, 0xFF, id, byte(length), inst)
+	pkt = append(pkt, params...)
+	pkt = append(pkt, checksum1(pkt[2:]))
+
+	return pkt
+}
+
+// ParsePacket validates a Protocol 1.0 status packet
+func (protocol1) ParsePacket(packet []byte) (id uint8, errCode uint8, params []byte, err error) {
+	// Min packet size: H(2)+ID(1)+Len(1)+Err(1)+Checksum(1) = 6 bytes
+	if len(packet) < 6 {
+		return 0, 0, nil, errors.New("packet too short")
+	}
+
+	if packet[0] != 0xFF || packet[1] != 0xFF {
+		return 0, 0, nil, errors.New("invalid header")
+	}
+
+	id = packet[2]
+	length := int(packet[3])
+
+	if len(packet) != length+p1HeaderSize {
+		return 0, 0, nil, fmt.Errorf("length mismatch: expected %d, got %d", length+p1HeaderSize, len(packet))
+	}
+
+	received := packet[len(packet)-1]
+	calc := checksum1(packet[2 : len(packet)-1])
+	if received != calc {
+		return 0, 0, nil, fmt.Errorf("checksum error: expected %02X, got %02X", calc, received)
+	}
+
+	errCode = packet[4]
+	if len(packet) > 6 {
+		params = packet[5 : len(packet)-1]
+	}
+
+	return id, errCode, params, nil
+}
+
+// frame finds the first Protocol 1.0 packet (FF FF followed by a non-FF ID)
+func (protocol1) frame(buf []byte) (int, int) {
+	start := -1
+	for i := 0; i < len(buf)-2; i++ {
+		if buf[i] == 0xFF && buf[i+1] == 0xFF && buf[i+2] != 0xFF {
+			start = i
+			break
+		}
+	}
+	if start == -1 || len(buf) < start+p1HeaderSize {
+		return start, 0
+	}
+	end := start + p1HeaderSize + int(buf[start+3])
+	if len(buf) < end {
+		return start, 0
+	}
+	return start, end
+}
+
+func (protocol1) wordSize() int { return 1 }
+
+// supports reports the instructions available in Protocol 1.0.
+// Sync Read, Bulk Write, Reboot, Clear, Backup and the fast read variants are Protocol 2.0 only.
+func (protocol1) supports(inst uint8) bool {
+	switch inst {
+	case InstPing, InstRead, InstWrite, InstRegWrite, InstAction,
+		InstFactoryReset, InstSyncWrite, InstBulkRead:
+		return true
+	}
+	return false
+}
+
+func (protocol1) statusError(id, errCode uint8) error { return decodeStatus1(id, errCode) }
@@ -0,0 +1,88 @@
+package dxl
+
+import (
+	"bytes"
+	"testing"
+)
+
+// buildStatusPacket1 creates a valid Protocol 1.0 status response packet
+func buildStatusPacket1(id uint8, errCode uint8, params []byte) []byte {
+	pkt := []byte{0xFF, 0xFF, id, byte(2 + len(params)), errCode}
+	pkt = append(pkt, params...)
+	return append(pkt, checksum1(pkt[2:]))
+}
+
+func TestProtocol1BuildPacket(t *testing.T) {
+	// Reference packet from the AX-12 manual: ping ID 1 -> FF FF 01 02 01 FB
+	ping := Protocol1.BuildPacket(1, InstPing, nil)
+	if !bytes.Equal(ping, []byte{0xFF, 0xFF, 0x01, 0x02, 0x01, 0xFB}) {
+		t.Errorf("Ping packet: got %X", ping)
+	}
+
+	// Read present position (addr 36, 2 bytes) from ID 1 -> FF FF 01 04 02 24 02 D2
+	read := Protocol1.BuildPacket(1, InstRead, []byte{0x24, 0x02})
+	if !bytes.Equal(read, []byte{0xFF, 0xFF, 0x01, 0x04, 0x02, 0x24, 0x02, 0xD2}) {
+		t.Errorf("Read packet: got %X", read)
+	}
+}
+
+func TestProtocol1ParsePacket(t *testing.T) {
+	pkt := buildStatusPacket1(1, 0, []byte{0x00, 0x02})
+
+	id, errCode, params, err := Protocol1.ParsePacket(pkt)
+	if err != nil {
+		t.Fatalf("ParsePacket failed: %v", err)
+	}
+	if id != 1 || errCode != 0 {
+		t.Errorf("ID/Error mismatch: got %d/%02X", id, errCode)
+	}
+	if !bytes.Equal(params, []byte{0x00, 0x02}) {
+		t.Errorf("Params mismatch: got %X", params)
+	}
+}
+
+func TestProtocol1ParsePacketErrors(t *testing.T) {
+	valid := buildStatusPacket1(1, 0, []byte{0x10})
+	badChecksum := append([]byte{}, valid...)
+	badChecksum[len(badChecksum)-1] ^= 0xFF
+
+	tests := []struct {
+		name   string
+		packet []byte
+	}{
+		{"too short", []byte{0xFF, 0xFF, 0x01}},
+		{"invalid header", []byte{0x00, 0xFF, 0x01, 0x02, 0x00, 0xFC}},
+		{"length mismatch", valid[:len(valid)-1]},
+		{"bad checksum", badChecksum},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, _, err := Protocol1.ParsePacket(tt.packet); err == nil {
+				t.Error("Expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestProtocol1Frame(t *testing.T) {
+	pkt := buildStatusPacket1(3, 0, []byte{0x01, 0x02})
+	buf := append([]byte{0x00, 0xFF}, pkt...)
+
+	start, end := Protocol1.frame(buf)
+	if start != 2 || end != 2+len(pkt) {
+		t.Errorf("frame() = (%d, %d), want (2, %d)", start, end, 2+len(pkt))
+	}
+
+	// Incomplete packet
+	start, end = Protocol1.frame(buf[:len(buf)-1])
+	if start != 2 || end != 0 {
+		t.Errorf("frame() on partial packet = (%d, %d), want (2, 0)", start, end)
+	}
+}
+
+func TestProtocolVersions(t *testing.T) {
+	if Protocol1.Version() != 1 || Protocol2.Version() != 2 {
+		t.Errorf("Versions: got %d and %d", Protocol1.Version(), Protocol2.Version())
+	}
+}
@@ -0,0 +1,242 @@
+package dxl
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// OverflowPolicy decides what a subscription does when its buffer is full.
+// The control loop never waits for a subscriber.
+type OverflowPolicy int
+
+const (
+	DropOldest OverflowPolicy = iota // Discard the oldest buffered sample (consumers see the newest data)
+	DropNewest                       // Discard the incoming sample (consumers see a gap-free prefix)
+)
+
+func (p OverflowPolicy) String() string {
+	switch p {
+	case DropOldest:
+		return "drop-oldest"
+	case DropNewest:
+		return "drop-newest"
+	}
+	return fmt.Sprintf("OverflowPolicy(%d)", int(p))
+}
+
+// BufferPolicy sizes a subscription's channel and picks its overflow policy
+type BufferPolicy struct {
+	Size     int // Channel capacity, at least 1
+	Overflow OverflowPolicy
+}
+
+// LatestOnly buffers a single sample and always replaces it with the newest,
+// e.g. for a UI that redraws at its own rate
+var LatestOnly = BufferPolicy{Size: 1, Overflow: DropOldest}
+
+// FeedbackFilter selects what a subscription receives. The zero value passes
+// every motor on every cycle.
+type FeedbackFilter struct {
+	IDs   []uint8 // Motors to include; empty = all
+	Every int     // Deliver every Nth cycle (decimation); 0 or 1 = every cycle
+}
+
+// Subscription is one consumer of control loop feedback
+type Subscription struct {
+	C <-chan []Feedback
+
+	ch      chan []Feedback
+	filter  FeedbackFilter
+	ids     map[uint8]bool
+	policy  BufferPolicy
+	mu      sync.Mutex // Guards send against Close
+	closed  bool
+	cycles  uint64 // Cycles seen, for Every (control loop only)
+	dropped atomic.Uint64
+	ctrl    *Controller
+}
+
+// Dropped returns how many samples were discarded because the buffer was full
+func (s *Subscription) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Close unsubscribes and closes C. Safe to call more than once.
+func (s *Subscription) Close() {
+	s.ctrl.removeSubscription(s)
+	s.close()
+}
+
+func (s *Subscription) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.closed {
+		s.closed = true
+		close(s.ch)
+	}
+}
+
+// publish delivers one cycle of feedback according to the filter and policy
+func (s *Subscription) publish(feedbacks []Feedback) {
+	s.cycles++
+	if s.filter.Every > 1 && (s.cycles-1)%uint64(s.filter.Every) != 0 {
+		return
+	}
+	sample := make([]Feedback, 0, len(feedbacks))
+	for _, fb := range feedbacks {
+		if s.ids == nil || s.ids[fb.ID] {
+			sample = append(sample, fb)
+		}
+	}
+	if len(sample) == 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	if s.policy.Overflow == DropNewest {
+		select {
+		case s.ch <- sample:
+		default:
+			s.dropped.Add(1)
+		}
+		return
+	}
+	if sendDropOldest(s.ch, sample) {
+		s.dropped.Add(1)
+	}
+}
+
+// sendDropOldest sends v on ch, discarding the oldest buffered value if ch is
+// full. It never blocks. Concurrent senders on one channel may each discard a
+// value to make room for one, losing more than needed, so callers serialize
+// their sends. Returns true if a value was dropped.
+func sendDropOldest[T any](ch chan T, v T) bool {
+	dropped := false
+	for {
+		select {
+		case ch <- v:
+			return dropped
+		default:
+		}
+		select {
+		case <-ch:
+			dropped = true
+		default:
+			// A consumer emptied it meanwhile; retry the send
+		}
+	}
+}
+
+// Subscribe registers an independent feedback consumer. Every subscriber gets
+// its own copy of each sample (filtered by motor ID and decimated per filter);
+// a slow subscriber only loses its own samples. C is closed by Close or when
+// the control loop stops.
+// Thread-safe: can be called while control loop is running
+func (c *Controller) Subscribe(filter FeedbackFilter, policy BufferPolicy) (*Subscription, error) {
+	if policy.Size < 1 {
+		return nil, fmt.Errorf("subscription buffer size must be at least 1, got %d", policy.Size)
+	}
+	if policy.Overflow != DropOldest && policy.Overflow != DropNewest {
+		return nil, fmt.Errorf("unknown overflow policy %v", policy.Overflow)
+	}
+	if filter.Every < 0 {
+		return nil, fmt.Errorf("filter Every must not be negative, got %d", filter.Every)
+	}
+	ch := make(chan []Feedback, policy.Size)
+	s := &Subscription{C: ch, ch: ch, filter: filter, policy: policy, ctrl: c}
+	if len(filter.IDs) > 0 {
+		s.ids = make(map[uint8]bool, len(filter.IDs))
+		for _, id := range filter.IDs {
+			s.ids[id] = true
+		}
+	}
+
+	c.subMu.Lock()
+	defer c.subMu.Unlock()
+	var subs []*Subscription
+	if cur := c.subs.Load(); cur != nil {
+		subs = append(subs, *cur...)
+	}
+	subs = append(subs, s)
+	c.subs.Store(&subs)
+	return s, nil
+}
+
+// removeSubscription drops s from the published list (copy-on-write)
+func (c *Controller) removeSubscription(s *Subscription) {
+	c.subMu.Lock()
+	defer c.subMu.Unlock()
+	cur := c.subs.Load()
+	if cur == nil {
+		return
+	}
+	subs := make([]*Subscription, 0, len(*cur))
+	for _, other := range *cur {
+		if other != s {
+			subs = append(subs, other)
+		}
+	}
+	c.subs.Store(&subs)
+}
+
+// closeSubscriptions closes every subscription when the control loop exits
+func (c *Controller) closeSubscriptions() {
+	c.subMu.Lock()
+	cur := c.subs.Swap(nil)
+	c.subMu.Unlock()
+	if cur == nil {
+		return
+	}
+	for _, s := range *cur {
+		s.close()
+	}
+}
+
+// publishFeedback stores the latest sample and hands it to FeedbackChan and
+// all subscribers, never blocking the control loop
+func (c *Controller) publishFeedback(feedbacks []Feedback) {
+	c.latest.Store(&feedbacks)
+
+	// FeedbackChan: keep the newest samples, discard the oldest
+	if c.FeedbackChan != nil {
+		sendDropOldest(c.FeedbackChan, feedbacks)
+	}
+
+	if subs := c.subs.Load(); subs != nil {
+		for _, s := range *subs {
+			s.publish(feedbacks)
+		}
+	}
+}
+
+// LatestState returns the most recent feedback of all motors without
+// touching any channel; nil before the first cycle. Lock-free, safe to call
+// from any goroutine at any rate. The returned slice is a copy.
+func (c *Controller) LatestState() []Feedback {
+	cur := c.latest.Load()
+	if cur == nil {
+		return nil
+	}
+	out := make([]Feedback, len(*cur))
+	copy(out, *cur)
+	return out
+}
+
+// LatestFeedback returns the most recent feedback of one motor (lock-free)
+func (c *Controller) LatestFeedback(id uint8) (Feedback, bool) {
+	cur := c.latest.Load()
+	if cur == nil {
+		return Feedback{}, false
+	}
+	for _, fb := range *cur {
+		if fb.ID == id {
+			return fb, true
+		}
+	}
+	return Feedback{}, false
+}
@@ -0,0 +1,120 @@
+package dxl
+
+import (
+	"testing"
+)
+
+func sample(seq uint64, ids ...uint8) []Feedback {
+	fbs := make([]Feedback, 0, len(ids))
+	for _, id := range ids {
+		fbs = append(fbs, Feedback{ID: id, Seq: seq, Value: uint32(seq)})
+	}
+	return fbs
+}
+
+func TestFeedbackChanDropsOldest(t *testing.T) {
+	ctrl := NewController("test", 1000000, ModelXSeries)
+	ctrl.FeedbackChan = make(chan []Feedback, 2)
+	for seq := uint64(1); seq <= 4; seq++ {
+		ctrl.publishFeedback(sample(seq, 1))
+	}
+	// Oldest (1, 2) discarded, newest kept
+	for _, want := range []uint64{3, 4} {
+		if got := (<-ctrl.FeedbackChan)[0].Seq; got != want {
+			t.Errorf("Seq = %d, want %d", got, want)
+		}
+	}
+}
+
+func TestSubscribeIndependentConsumers(t *testing.T) {
+	ctrl := NewController("test", 1000000, ModelXSeries)
+	all, err := ctrl.Subscribe(FeedbackFilter{}, BufferPolicy{Size: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wrist, _ := ctrl.Subscribe(FeedbackFilter{IDs: []uint8{2}, Every: 2}, BufferPolicy{Size: 10})
+	ui, _ := ctrl.Subscribe(FeedbackFilter{}, LatestOnly)
+	planner, _ := ctrl.Subscribe(FeedbackFilter{}, BufferPolicy{Size: 2, Overflow: DropNewest})
+
+	for seq := uint64(1); seq <= 5; seq++ {
+		ctrl.publishFeedback(sample(seq, 1, 2))
+	}
+
+	if n := len(all.C); n != 5 {
+		t.Errorf("all: %d samples, want 5", n)
+	}
+	// Every 2nd cycle, motor 2 only: cycles 1, 3, 5
+	for _, want := range []uint64{1, 3, 5} {
+		fbs := <-wrist.C
+		if len(fbs) != 1 || fbs[0].ID != 2 || fbs[0].Seq != want {
+			t.Errorf("wrist got %+v, want ID 2 seq %d", fbs, want)
+		}
+	}
+	if fbs := <-ui.C; fbs[0].Seq != 5 || ui.Dropped() != 4 {
+		t.Errorf("ui got seq %d dropped %d, want 5 and 4", fbs[0].Seq, ui.Dropped())
+	}
+	if fbs := <-planner.C; fbs[0].Seq != 1 || planner.Dropped() != 3 {
+		t.Errorf("planner got seq %d dropped %d, want 1 and 3", fbs[0].Seq, planner.Dropped())
+	}
+
+	// Each subscriber owns its slice
+	fbs := <-all.C
+	fbs[0].Value = 999
+	if got := (<-planner.C)[0].Value; got != 2 {
+		t.Errorf("planner sample modified through another subscriber: %d", got)
+	}
+
+	all.Close()
+	all.Close() // Idempotent
+	ctrl.publishFeedback(sample(6, 1))
+	for range all.C {
+	}
+	if _, ok := <-all.C; ok {
+		t.Error("closed subscription still open")
+	}
+}
+
+func TestSubscribeValidation(t *testing.T) {
+	ctrl := NewController("test", 1000000, ModelXSeries)
+	if _, err := ctrl.Subscribe(FeedbackFilter{}, BufferPolicy{}); err == nil {
+		t.Error("expected error for zero buffer size")
+	}
+	if _, err := ctrl.Subscribe(FeedbackFilter{}, BufferPolicy{Size: 1, Overflow: 7}); err == nil {
+		t.Error("expected error for unknown overflow policy")
+	}
+	if _, err := ctrl.Subscribe(FeedbackFilter{Every: -1}, LatestOnly); err == nil {
+		t.Error("expected error for negative Every")
+	}
+}
+
+func TestLatestState(t *testing.T) {
+	ctrl := NewController("test", 1000000, ModelXSeries)
+	if ctrl.LatestState() != nil {
+		t.Error("LatestState before first cycle should be nil")
+	}
+	ctrl.publishFeedback(sample(1, 1, 2))
+	ctrl.publishFeedback(sample(2, 1, 2))
+
+	st := ctrl.LatestState()
+	if len(st) != 2 || st[0].Seq != 2 {
+		t.Fatalf("LatestState = %+v", st)
+	}
+	st[0].Seq = 0 // A copy: must not change the stored sample
+	if fb, ok := ctrl.LatestFeedback(1); !ok || fb.Seq != 2 {
+		t.Errorf("LatestFeedback(1) = %+v, %v", fb, ok)
+	}
+	if _, ok := ctrl.LatestFeedback(9); ok {
+		t.Error("LatestFeedback(9) found unknown motor")
+	}
+}
+
+func TestSubscriptionsClosedWhenLoopStops(t *testing.T) {
+	ctrl := newTestController(NewMockSerialPort(), []uint8{1})
+	sub, _ := ctrl.Subscribe(FeedbackFilter{}, LatestOnly)
+	ctrl.wg.Add(1)
+	go ctrl.controlLoop()
+	<-sub.C // At least one cycle
+	ctrl.Stop()
+	for range sub.C {
+	}
+}
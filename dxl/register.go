@@ -0,0 +1,135 @@
+package dxl
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// SetModel assigns the control table used by ReadItem/WriteItem for a motor,
+// skipping the ping-and-lookup on first access
+func (d *Driver) SetModel(id uint8, def *ModelDefinition) {
+	if d.models == nil {
+		d.models = make(map[uint8]*ModelDefinition)
+	}
+	d.models[id] = def
+}
+
+// ModelOf returns the control table of a motor. The first call pings the
+// motor and looks its model number up in the registry; the result is cached.
+func (d *Driver) ModelOf(id uint8) (*ModelDefinition, error) {
+	if def, ok := d.models[id]; ok {
+		return def, nil
+	}
+	if id == BroadcastID {
+		return nil, fmt.Errorf("item access needs a single motor, not broadcast")
+	}
+	number, err := d.Ping(id)
+	if err != nil && !isAlertOnly(err) {
+		return nil, fmt.Errorf("ping ID %d: %w", id, err)
+	}
+	def, ok := LookupModel(number)
+	if !ok {
+		return nil, fmt.Errorf("motor %d has unknown model number %d", id, number)
+	}
+	d.SetModel(id, def)
+	return def, nil
+}
+
+// resolveItem finds a named item in the motor's control table
+func (d *Driver) resolveItem(id uint8, name string) (ControlItem, error) {
+	def, err := d.ModelOf(id)
+	if err != nil {
+		return ControlItem{}, err
+	}
+	it, ok := def.Item(name)
+	if !ok {
+		return ControlItem{}, fmt.Errorf("%s has no item %q", def.Name, name)
+	}
+	return it, nil
+}
+
+// ReadItem reads a control table item by name, e.g. "Present_Position".
+// Signed items are sign-extended. As with Read, a hardware alert is returned
+// together with the value.
+func (d *Driver) ReadItem(id uint8, name string) (int64, error) {
+	it, err := d.resolveItem(id, name)
+	if err != nil {
+		return 0, err
+	}
+	data, err := d.Read(id, it.Addr, uint16(it.Size))
+	if err != nil && !isAlertOnly(err) {
+		return 0, err
+	}
+	v, decErr := decodeItem(it, data)
+	if decErr != nil {
+		return 0, decErr
+	}
+	return v, err
+}
+
+// WriteItem writes a control table item by name, e.g. "Goal_Current".
+// Read-only items and values outside the item's range are rejected before
+// anything is sent.
+func (d *Driver) WriteItem(id uint8, name string, value int64) error {
+	it, err := d.resolveItem(id, name)
+	if err != nil {
+		return err
+	}
+	data, err := encodeItem(it, value)
+	if err != nil {
+		return err
+	}
+	return d.Write(id, it.Addr, data)
+}
+
+// decodeItem converts little-endian item bytes to a value
+func decodeItem(it ControlItem, data []byte) (int64, error) {
+	if len(data) != int(it.Size) {
+		return 0, fmt.Errorf("%s: expected %d bytes, got %d", it.Name, it.Size, len(data))
+	}
+	switch it.Size {
+	case 1:
+		if it.Signed {
+			return int64(int8(data[0])), nil
+		}
+		return int64(data[0]), nil
+	case 2:
+		v := binary.LittleEndian.Uint16(data)
+		if it.Signed {
+			return int64(int16(v)), nil
+		}
+		return int64(v), nil
+	case 4:
+		v := binary.LittleEndian.Uint32(data)
+		if it.Signed {
+			return int64(int32(v)), nil
+		}
+		return int64(v), nil
+	}
+	return 0, fmt.Errorf("%s: invalid size %d", it.Name, it.Size)
+}
+
+// encodeItem checks access and range and converts a value to item bytes
+func encodeItem(it ControlItem, value int64) ([]byte, error) {
+	if it.Access != AccessReadWrite {
+		return nil, fmt.Errorf("%s is read-only", it.Name)
+	}
+	if it.HasRange() && (value < it.Min || value > it.Max) {
+		return nil, fmt.Errorf("%s: value %d out of range [%d, %d]", it.Name, value, it.Min, it.Max)
+	}
+
+	bits := 8 * uint(it.Size)
+	var lo, hi int64
+	if it.Signed {
+		lo, hi = -(1 << (bits - 1)), 1<<(bits-1)-1
+	} else {
+		lo, hi = 0, 1<<bits-1
+	}
+	if value < lo || value > hi {
+		return nil, fmt.Errorf("%s: value %d does not fit in %d bytes", it.Name, value, it.Size)
+	}
+
+	data := make([]byte, 4)
+	binary.LittleEndian.PutUint32(data, uint32(value))
+	return data[:it.Size], nil
+}
@@ -0,0 +1,97 @@
+package dxl
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDriverReadItemSignExtends(t *testing.T) {
+	mock := NewScriptedSerialPort(
+		buildStatusPacket(1, 0, []byte{0xFC, 0x03, 0x2A}),       // Ping: model 1020 (XM430-W350)
+		buildStatusPacket(1, 0, []byte{0xF6, 0xFF, 0xFF, 0xFF}), // Present Velocity = -10
+		buildStatusPacket(1, 0, []byte{0xFF, 0xFF}),             // Present Input Voltage (unsigned)
+	)
+	d := NewDriver(mock)
+
+	v, err := d.ReadItem(1, "Present_Velocity")
+	if err != nil {
+		t.Fatalf("ReadItem failed: %v", err)
+	}
+	if v != -10 {
+		t.Errorf("Present_Velocity: got %d, want -10", v)
+	}
+
+	// Model is cached: no second ping
+	v, err = d.ReadItem(1, "Present_Input_Voltage")
+	if err != nil {
+		t.Fatalf("ReadItem failed: %v", err)
+	}
+	if v != 0xFFFF {
+		t.Errorf("Present_Input_Voltage: got %d, want 65535", v)
+	}
+}
+
+func TestDriverWriteItem(t *testing.T) {
+	mock := NewScriptedSerialPort(buildStatusPacket(1, 0, nil))
+	d := NewDriver(mock)
+	def, _ := LookupModel(1020)
+	d.SetModel(1, def)
+
+	if err := d.WriteItem(1, "Goal_Current", -100); err != nil {
+		t.Fatalf("WriteItem failed: %v", err)
+	}
+	// Params: addr 102 (0x66 0x00), value -100 as int16 LE (0x9C 0xFF)
+	want := []byte{0x66, 0x00, 0x9C, 0xFF}
+	if !bytes.Contains(mock.GetWritten(), want) {
+		t.Errorf("Expected params %X in %X", want, mock.GetWritten())
+	}
+}
+
+func TestDriverWriteItemRejected(t *testing.T) {
+	mock := NewScriptedSerialPort()
+	d := NewDriver(mock)
+	def, _ := LookupModel(1020)
+	d.SetModel(1, def)
+
+	cases := []struct {
+		name  string
+		value int64
+	}{
+		{"Present_Position", 0}, // read-only
+		{"Goal_Current", 1194},  // above Current Limit range
+		{"Torque_Enable", -1},   // below range
+		{"No_Such_Item", 0},     // unknown
+	}
+	for _, c := range cases {
+		if err := d.WriteItem(1, c.name, c.value); err == nil {
+			t.Errorf("%s=%d: expected error", c.name, c.value)
+		}
+	}
+	if len(mock.GetWritten()) != 0 {
+		t.Errorf("Rejected writes must not reach the bus, wrote %X", mock.GetWritten())
+	}
+}
+
+func TestDriverModelOfUnknown(t *testing.T) {
+	mock := NewScriptedSerialPort(buildStatusPacket(1, 0, []byte{0x0F, 0x27, 0x01})) // model 9999
+	d := NewDriver(mock)
+	if _, err := d.ReadItem(1, "Present_Position"); err == nil {
+		t.Error("Expected error for unregistered model")
+	}
+}
+
+func TestEncodeItemWidth(t *testing.T) {
+	// No range: only the byte width limits the value
+	it := ControlItem{Name: "Raw", Size: 2, Access: AccessReadWrite, Memory: RAM}
+	if _, err := encodeItem(it, 65536); err == nil {
+		t.Error("Expected overflow error for unsigned 2-byte item")
+	}
+	it.Signed = true
+	if _, err := encodeItem(it, -32769); err == nil {
+		t.Error("Expected overflow error for signed 2-byte item")
+	}
+	data, err := encodeItem(it, -32768)
+	if err != nil || !bytes.Equal(data, []byte{0x00, 0x80}) {
+		t.Errorf("encodeItem(-32768): got %X, %v", data, err)
+	}
+}
@@ -0,0 +1,354 @@
+package dxl
+
+import (
+	"fmt"
+	"time"
+)
+
+// SafetyPolicy decides what happens to a goal that breaks a safety limit
+type SafetyPolicy int
+
+const (
+	SafetyClamp  SafetyPolicy = iota // Write the goal clamped to the limit
+	SafetyReject                     // Drop the goal, keep the previous one
+	SafetyFault                      // Drop the goal and latch a fault: further goals for the motor are dropped until ClearSafetyFault
+)
+
+func (p SafetyPolicy) String() string {
+	switch p {
+	case SafetyClamp:
+		return "clamp"
+	case SafetyReject:
+		return "reject"
+	case SafetyFault:
+		return "fault"
+	}
+	return fmt.Sprintf("SafetyPolicy(%d)", int(p))
+}
+
+// LimitKind identifies which safety limit triggered
+type LimitKind int
+
+const (
+	LimitPosition LimitKind = iota // Goal position outside MinPosition..MaxPosition
+	LimitVelocity                  // |Goal velocity| above MaxVelocity
+	LimitCurrent                   // |Goal current| above MaxCurrent
+	LimitPWM                       // |Goal PWM| above MaxPWM
+	LimitStep                      // Change from the previous goal above the step limit
+	LimitFaulted                   // Goal dropped because the motor has a latched safety fault
+)
+
+func (k LimitKind) String() string {
+	switch k {
+	case LimitPosition:
+		return "position"
+	case LimitVelocity:
+		return "velocity"
+	case LimitCurrent:
+		return "current"
+	case LimitPWM:
+		return "pwm"
+	case LimitStep:
+		return "step"
+	case LimitFaulted:
+		return "faulted"
+	}
+	return fmt.Sprintf("LimitKind(%d)", int(k))
+}
+
+// SafetyLimits is a motor's software envelope, enforced on every goal before
+// it is written. All values are raw register units; a zero cap is unlimited.
+type SafetyLimits struct {
+	MinPosition int32 // Goal position range; unchecked when both are 0
+	MaxPosition int32
+	MaxVelocity int32 // |Goal Velocity|
+	MaxCurrent  int16 // |Goal Current|, also for Command.WithCurrent
+	MaxPWM      int16 // |Goal PWM|
+
+	// Largest change between consecutive goals of the same register. At a
+	// fixed control rate (SetControlRate) these are rate-of-change limits.
+	// The first position step is measured from the present position.
+	MaxPositionStep int32
+	MaxVelocityStep int32
+	MaxCurrentStep  int16
+	MaxPWMStep      int16
+
+	Policy SafetyPolicy
+}
+
+// HasPositionRange reports whether the position range is set
+func (l SafetyLimits) HasPositionRange() bool {
+	return l.MinPosition != 0 || l.MaxPosition != 0
+}
+
+func (l SafetyLimits) validate() error {
+	if l.HasPositionRange() && l.MinPosition > l.MaxPosition {
+		return fmt.Errorf("MinPosition %d above MaxPosition %d", l.MinPosition, l.MaxPosition)
+	}
+	if l.MaxVelocity < 0 || l.MaxCurrent < 0 || l.MaxPWM < 0 ||
+		l.MaxPositionStep < 0 || l.MaxVelocityStep < 0 || l.MaxCurrentStep < 0 || l.MaxPWMStep < 0 {
+		return fmt.Errorf("caps and step limits must not be negative")
+	}
+	if l.Policy < SafetyClamp || l.Policy > SafetyFault {
+		return fmt.Errorf("unknown safety policy %v", l.Policy)
+	}
+	return nil
+}
+
+// SafetyEvent reports a goal that hit a safety limit
+type SafetyEvent struct {
+	Time      time.Time
+	ID        uint8
+	Limit     LimitKind
+	Policy    SafetyPolicy
+	Requested int64 // Raw goal as commanded
+	Limited   int64 // Goal after the limit; written only with SafetyClamp
+}
+
+func (e SafetyEvent) String() string {
+	return fmt.Sprintf("motor %d: %s limit (%s) requested %d limited %d", e.ID, e.Limit, e.Policy, e.Requested, e.Limited)
+}
+
+// SetSafetyLimits sets a motor's safety envelope, replacing any previous one.
+// Thread-safe: can be called while control loop is running
+func (c *Controller) SetSafetyLimits(id uint8, limits SafetyLimits) error {
+	if err := limits.validate(); err != nil {
+		return fmt.Errorf("safety limits for ID %d: %v", id, err)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.safetyLimits == nil {
+		c.safetyLimits = make(map[uint8]SafetyLimits)
+	}
+	c.safetyLimits[id] = limits
+	return nil
+}
+
+// SafetyLimitsFor returns a motor's safety envelope
+func (c *Controller) SafetyLimitsFor(id uint8) (SafetyLimits, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	l, ok := c.safetyLimits[id]
+	return l, ok
+}
+
+// SafetyFaulted reports whether a motor has a latched safety fault
+func (c *Controller) SafetyFaulted(id uint8) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.safetyFaults[id]
+}
+
+// ClearSafetyFault re-arms a motor after a SafetyFault. The next position
+// step is measured from the present position again.
+func (c *Controller) ClearSafetyFault(id uint8) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.safetyFaults, id)
+	delete(c.lastGoals, id)
+}
+
+// emitSafety stamps an event and publishes it on SafetyEvents, dropping the
+// oldest if full. The control loop and StageGoals both check goals, so sends
+// are serialized to keep the channel in time order.
+func (c *Controller) emitSafety(ev SafetyEvent) {
+	if c.SafetyEvents == nil {
+		return
+	}
+	c.eventMu.Lock()
+	ev.Time = time.Now()
+	sendDropOldest(c.SafetyEvents, ev)
+	c.eventMu.Unlock()
+}
+
+// lastGoalsOf copies the step-limit history of some motors
+func (c *Controller) lastGoalsOf(ids []uint8) map[uint8]map[uint16]int64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	saved := make(map[uint8]map[uint16]int64, len(ids))
+	for _, id := range ids {
+		saved[id] = make(map[uint16]int64, len(c.lastGoals[id]))
+		for addr, v := range c.lastGoals[id] {
+			saved[id][addr] = v
+		}
+	}
+	return saved
+}
+
+// forgetGoals restores the step-limit history from prev for goals grouped
+// but never written. Entries a newer goal replaced meanwhile are kept.
+func (c *Controller) forgetGoals(groups []goalGroup, prev map[uint8]map[uint16]int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, g := range groups {
+		for _, id := range g.ids {
+			if cur, ok := c.lastGoals[id][g.addr]; !ok || cur != decodeGoal(g.values[id], g.size) {
+				continue
+			}
+			if v, ok := prev[id][g.addr]; ok {
+				c.lastGoals[id][g.addr] = v
+			} else {
+				delete(c.lastGoals[id], g.addr)
+			}
+		}
+	}
+}
+
+// goalKind maps a goal register to the limit that applies to it
+func goalKind(m MotorModel, addr uint16) LimitKind {
+	switch addr {
+	case m.AddrGoalVelocity:
+		return LimitVelocity
+	case m.AddrGoalCurrent:
+		return LimitCurrent
+	case m.AddrGoalPWM:
+		return LimitPWM
+	}
+	return LimitPosition
+}
+
+// decodeGoal reads a raw goal as the signed value of its register
+func decodeGoal(value uint32, size int) int64 {
+	if size == 2 {
+		return int64(int16(value))
+	}
+	return int64(int32(value))
+}
+
+func clampInt(v, lo, hi int64) int64 {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
+
+// enforceLimits applies a motor's envelope to one raw goal. It returns the
+// goal to write, or false if the goal must be dropped.
+func (c *Controller) enforceLimits(id uint8, addr uint16, size int, value uint32) (uint32, bool) {
+	m := c.ModelFor(id)
+	kind := goalKind(m, addr)
+	requested := decodeGoal(value, size)
+
+	c.mu.Lock()
+	limits, hasLimits := c.safetyLimits[id]
+	if c.safetyFaults[id] {
+		c.mu.Unlock()
+		c.emitSafety(SafetyEvent{ID: id, Limit: LimitFaulted, Policy: SafetyFault, Requested: requested, Limited: requested})
+		return 0, false
+	}
+	if !hasLimits {
+		c.mu.Unlock()
+		return value, true
+	}
+	prev, hasPrev := c.lastGoals[id][addr]
+	c.mu.Unlock()
+	if !hasPrev && kind == LimitPosition {
+		if fb, ok := c.LatestFeedback(id); ok && fb.Error == nil && fb.State.Has(FieldPosition) {
+			prev, hasPrev = int64(fb.State.Position), true
+		}
+	}
+
+	applied := requested
+	triggered := LimitKind(-1)
+	var step int64
+	switch kind {
+	case LimitPosition:
+		if limits.HasPositionRange() {
+			applied = clampInt(applied, int64(limits.MinPosition), int64(limits.MaxPosition))
+		}
+		step = int64(limits.MaxPositionStep)
+	case LimitVelocity:
+		if limits.MaxVelocity > 0 {
+			applied = clampInt(applied, -int64(limits.MaxVelocity), int64(limits.MaxVelocity))
+		}
+		step = int64(limits.MaxVelocityStep)
+	case LimitCurrent:
+		if limits.MaxCurrent > 0 {
+			applied = clampInt(applied, -int64(limits.MaxCurrent), int64(limits.MaxCurrent))
+		}
+		step = int64(limits.MaxCurrentStep)
+	case LimitPWM:
+		if limits.MaxPWM > 0 {
+			applied = clampInt(applied, -int64(limits.MaxPWM), int64(limits.MaxPWM))
+		}
+		step = int64(limits.MaxPWMStep)
+	}
+	if applied != requested {
+		triggered = kind
+	}
+	if step > 0 && hasPrev {
+		if stepped := clampInt(applied, prev-step, prev+step); stepped != applied {
+			applied = stepped
+			if triggered < 0 {
+				triggered = LimitStep
+			}
+		}
+	}
+
+	if triggered >= 0 && limits.Policy != SafetyClamp {
+		c.mu.Lock()
+		if limits.Policy == SafetyFault {
+			if c.safetyFaults == nil {
+				c.safetyFaults = make(map[uint8]bool)
+			}
+			c.safetyFaults[id] = true
+		}
+		c.mu.Unlock()
+		c.emitSafety(SafetyEvent{ID: id, Limit: triggered, Policy: limits.Policy, Requested: requested, Limited: applied})
+		return 0, false
+	}
+
+	c.mu.Lock()
+	if c.lastGoals == nil {
+		c.lastGoals = make(map[uint8]map[uint16]int64)
+	}
+	if c.lastGoals[id] == nil {
+		c.lastGoals[id] = make(map[uint16]int64)
+	}
+	c.lastGoals[id][addr] = applied
+	c.mu.Unlock()
+
+	if triggered >= 0 {
+		c.emitSafety(SafetyEvent{ID: id, Limit: triggered, Policy: SafetyClamp, Requested: requested, Limited: applied})
+	}
+	return uint32(applied), true
+}
+
+// checkPositionLimitRegisters cross-checks software position ranges against
+// the motors' Min/Max Position Limit registers, which the motor itself
+// enforces in position mode. An envelope reaching beyond them is a
+// configuration error: those goals would never be reached.
+func (c *Controller) checkPositionLimitRegisters(ids []uint8) error {
+	for _, id := range ids {
+		limits, ok := c.SafetyLimitsFor(id)
+		if !ok || !limits.HasPositionRange() {
+			continue
+		}
+		if mode, known := c.OperatingMode(id); !known || mode != OpModePosition {
+			continue // Registers are ignored outside position mode
+		}
+		def, ok := c.MotorDefinition(id)
+		if !ok {
+			continue
+		}
+		if _, ok := def.Item("Min_Position_Limit"); !ok {
+			continue
+		}
+		lo, err := c.ReadItem(id, "Min_Position_Limit")
+		if err != nil && !isAlertOnly(err) {
+			return fmt.Errorf("failed to read Min Position Limit for ID %d: %v", id, err)
+		}
+		hi, err := c.ReadItem(id, "Max_Position_Limit")
+		if err != nil && !isAlertOnly(err) {
+			return fmt.Errorf("failed to read Max Position Limit for ID %d: %v", id, err)
+		}
+		if int64(limits.MinPosition) < lo || int64(limits.MaxPosition) > hi {
+			return fmt.Errorf("motor %d: safety envelope %d..%d exceeds Position Limit registers %d..%d",
+				id, limits.MinPosition, limits.MaxPosition, lo, hi)
+		}
+	}
+	return nil
+}
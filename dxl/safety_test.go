@@ -0,0 +1,241 @@
+package dxl
+
+import (
+	"bytes"
+	"encoding/binary"
+	"runtime"
+	"sync"
+	"testing"
+)
+
+// goalValue returns the single goal grouped for a motor's register
+func goalValue(t *testing.T, groups []goalGroup, id uint8, addr uint16) (int64, bool) {
+	t.Helper()
+	for _, g := range groups {
+		if g.addr == addr {
+			v, ok := g.values[id]
+			return decodeGoal(v, g.size), ok
+		}
+	}
+	return 0, false
+}
+
+func TestSafetyClampPosition(t *testing.T) {
+	ctrl := newTestController(NewScriptedSerialPort(), []uint8{1})
+	if err := ctrl.SetSafetyLimits(1, SafetyLimits{MinPosition: 100, MaxPosition: 4000}); err != nil {
+		t.Fatal(err)
+	}
+
+	// 0xFFFFFFFF is -1 as a signed goal: clamped to the minimum
+	v, ok := goalValue(t, ctrl.groupGoals([]Command{{ID: 1, Value: 0xFFFFFFFF}}), 1, 116)
+	if !ok || v != 100 {
+		t.Errorf("goal = %d (%v), want 100", v, ok)
+	}
+	ev := <-ctrl.SafetyEvents
+	if ev.ID != 1 || ev.Limit != LimitPosition || ev.Policy != SafetyClamp || ev.Requested != -1 || ev.Limited != 100 {
+		t.Errorf("event = %+v", ev)
+	}
+
+	// In range: unchanged, no event
+	if v, _ := goalValue(t, ctrl.groupGoals([]Command{{ID: 1, Value: 2048}}), 1, 116); v != 2048 {
+		t.Errorf("goal = %d, want 2048", v)
+	}
+	if len(ctrl.SafetyEvents) != 0 {
+		t.Errorf("unexpected event %+v", <-ctrl.SafetyEvents)
+	}
+}
+
+func TestSafetyStepLimit(t *testing.T) {
+	ctrl := newTestController(NewScriptedSerialPort(), []uint8{1})
+	ctrl.SetSafetyLimits(1, SafetyLimits{MaxPositionStep: 50})
+	ctrl.publishFeedback([]Feedback{{ID: 1, State: MotorState{Fields: FieldPosition, Position: 1000}}})
+
+	// First step measured from the present position
+	if v, _ := goalValue(t, ctrl.groupGoals([]Command{{ID: 1, Value: 3000}}), 1, 116); v != 1050 {
+		t.Errorf("first goal = %d, want 1050", v)
+	}
+	// Then from the previous goal
+	if v, _ := goalValue(t, ctrl.groupGoals([]Command{{ID: 1, Value: 3000}}), 1, 116); v != 1100 {
+		t.Errorf("second goal = %d, want 1100", v)
+	}
+	if ev := <-ctrl.SafetyEvents; ev.Limit != LimitStep {
+		t.Errorf("event limit = %v, want step", ev.Limit)
+	}
+}
+
+func TestSafetyRejectAndFault(t *testing.T) {
+	ctrl := newTestController(NewScriptedSerialPort(), []uint8{1, 2})
+	ctrl.setMode(1, OpModeVelocity)
+	ctrl.SetSafetyLimits(1, SafetyLimits{MaxVelocity: 200, Policy: SafetyReject})
+	ctrl.SetSafetyLimits(2, SafetyLimits{MinPosition: 0, MaxPosition: 4095, Policy: SafetyFault})
+
+	groups := ctrl.groupGoals([]Command{{ID: 1, Value: uint32(0xFFFFFF00)}, {ID: 2, Value: 5000}}) // -256, 5000
+	if len(groups) != 0 {
+		t.Errorf("rejected goals were grouped: %+v", groups)
+	}
+	if ev := <-ctrl.SafetyEvents; ev.ID != 1 || ev.Limit != LimitVelocity || ev.Policy != SafetyReject || ev.Limited != -200 {
+		t.Errorf("event = %+v", ev)
+	}
+	if ev := <-ctrl.SafetyEvents; ev.ID != 2 || ev.Policy != SafetyFault {
+		t.Errorf("event = %+v", ev)
+	}
+
+	// Reject does not latch, fault does
+	if _, ok := goalValue(t, ctrl.groupGoals([]Command{{ID: 1, Value: 100}}), 1, 104); !ok {
+		t.Error("motor 1 should accept an in-range goal after a reject")
+	}
+	if !ctrl.SafetyFaulted(2) || ctrl.SafetyFaulted(1) {
+		t.Error("only motor 2 should be faulted")
+	}
+	if len(ctrl.groupGoals([]Command{{ID: 2, Value: 2048}})) != 0 {
+		t.Error("faulted motor accepted a goal")
+	}
+	if ev := <-ctrl.SafetyEvents; ev.Limit != LimitFaulted {
+		t.Errorf("event limit = %v, want faulted", ev.Limit)
+	}
+
+	ctrl.ClearSafetyFault(2)
+	if _, ok := goalValue(t, ctrl.groupGoals([]Command{{ID: 2, Value: 2048}}), 2, 116); !ok {
+		t.Error("motor 2 should accept goals after ClearSafetyFault")
+	}
+}
+
+func TestSafetyCurrentCap(t *testing.T) {
+	mock := NewScriptedSerialPort()
+	ctrl := newTestController(mock, []uint8{1})
+	ctrl.setMode(1, OpModeCurrentBasedPos)
+	ctrl.SetSafetyLimits(1, SafetyLimits{MaxCurrent: 300})
+
+	ctrl.writeGoals([]Command{{ID: 1, Value: 2048, WithCurrent: true, Current: -800}})
+	// -300 = 0xFED4
+	if !bytes.Contains(mock.GetWritten(), []byte{102, 0, 0xD4, 0xFE}) {
+		t.Errorf("goal current not capped to -300: %X", mock.GetWritten())
+	}
+}
+
+func TestSafetyLimitsValidation(t *testing.T) {
+	ctrl := NewController("test", 1000000, ModelXSeries)
+	bad := []SafetyLimits{
+		{MinPosition: 10, MaxPosition: 5},
+		{MaxVelocity: -1},
+		{MaxPositionStep: -1},
+		{Policy: 9},
+	}
+	for _, l := range bad {
+		if err := ctrl.SetSafetyLimits(1, l); err == nil {
+			t.Errorf("expected error for %+v", l)
+		}
+	}
+}
+
+func TestSafetyPositionLimitRegisters(t *testing.T) {
+	u32 := func(v uint32) []byte {
+		b := make([]byte, 4)
+		binary.LittleEndian.PutUint32(b, v)
+		return b
+	}
+	mock := NewScriptedSerialPort(
+		buildStatusPacket(1, 0, u32(1024)), // Min_Position_Limit
+		buildStatusPacket(1, 0, u32(3072)), // Max_Position_Limit
+	)
+	ctrl := newTestController(mock, []uint8{1})
+	ctrl.Model = MotorModel{}
+	if err := ctrl.configureModels([]uint8{1}, map[uint8]uint16{1: 1020}); err != nil {
+		t.Fatal(err)
+	}
+	ctrl.setMode(1, OpModePosition)
+	ctrl.SetSafetyLimits(1, SafetyLimits{MinPosition: 0, MaxPosition: 3000})
+
+	if err := ctrl.checkPositionLimitRegisters([]uint8{1}); err == nil {
+		t.Error("expected error: envelope below Min Position Limit")
+	}
+
+	// Outside position mode the registers do not apply and are not read
+	ctrl.setMode(1, OpModeExtendedPosition)
+	if err := ctrl.checkPositionLimitRegisters([]uint8{1}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSafetyStageGoals(t *testing.T) {
+	mock := NewScriptedSerialPort(
+		buildStatusPacket(1, 0, nil),       // Reg Write ID 1 (clamped)
+		buildStatusPacket(1, 0, []byte{1}), // Registered Instruction ID 1
+	)
+	ctrl := runningController(mock, []uint8{1, 2})
+	ctrl.SetSafetyLimits(1, SafetyLimits{MinPosition: 0, MaxPosition: 3000})
+	ctrl.SetSafetyLimits(2, SafetyLimits{MinPosition: 0, MaxPosition: 3000, Policy: SafetyReject})
+
+	if err := ctrl.StageGoals([]Command{{ID: 1, Value: 4000}, {ID: 2, Value: 4000}}); err != nil {
+		t.Fatalf("StageGoals failed: %v", err)
+	}
+	// Only motor 1 is staged, at the clamped position
+	want := ctrl.driver.Protocol().BuildPacket(1, InstRegWrite, []byte{116, 0, 0xB8, 0x0B, 0, 0})
+	if !bytes.HasPrefix(mock.GetWritten(), want) {
+		t.Errorf("first packet = %X, want %X", mock.GetWritten()[:len(want)], want)
+	}
+	if bytes.Contains(mock.GetWritten(), ctrl.driver.Protocol().BuildPacket(2, InstRegWrite, []byte{116, 0, 0xA0, 0x0F, 0, 0})) {
+		t.Error("rejected goal for motor 2 was staged")
+	}
+
+	// Nothing left to stage
+	if err := ctrl.StageGoals([]Command{{ID: 2, Value: 4000}}); err == nil {
+		t.Error("expected an error when every goal is rejected")
+	}
+}
+
+func TestSafetyEventsConcurrentSenders(t *testing.T) {
+	// Goals are checked by the control loop and StageGoals at once: events
+	// must reach SafetyEvents in the order they were stamped, and each
+	// sender's events in the order it sent them
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(8)) // Let senders interleave on one CPU too
+	ctrl := newTestController(NewScriptedSerialPort(), []uint8{1})
+	const senders, perSender = 8, 2000
+
+	var received []SafetyEvent
+	done := make(chan struct{})
+	consumed := make(chan struct{})
+	go func() {
+		defer close(consumed)
+		for {
+			select {
+			case ev := <-ctrl.SafetyEvents:
+				received = append(received, ev)
+			case <-done:
+				for {
+					select {
+					case ev := <-ctrl.SafetyEvents:
+						received = append(received, ev)
+					default:
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for g := 0; g < senders; g++ {
+		wg.Add(1)
+		go func(id uint8) {
+			defer wg.Done()
+			for i := 0; i < perSender; i++ {
+				ctrl.emitSafety(SafetyEvent{ID: id, Limit: LimitPosition, Requested: int64(i)})
+			}
+		}(uint8(g))
+	}
+	wg.Wait()
+	close(done)
+	<-consumed
+
+	last := make(map[uint8]int64)
+	for i, ev := range received {
+		if i > 0 && ev.Time.Before(received[i-1].Time) {
+			t.Fatalf("event %d stamped %v before its predecessor %v", i, ev.Time, received[i-1].Time)
+		}
+		if prev, ok := last[ev.ID]; ok && ev.Requested <= prev {
+			t.Fatalf("sender %d: event %d after %d", ev.ID, ev.Requested, prev)
+		}
+		last[ev.ID] = ev.Requested
+	}
+}
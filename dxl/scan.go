@@ -0,0 +1,131 @@
+package dxl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+const (
+	// MaxID is the highest addressable motor ID (253 is reserved, 254 is broadcast)
+	MaxID = 252
+
+	// ScanPingTimeout bounds each ping of a sequential scan, so probing
+	// unused IDs does not cost a full Driver.Timeout each
+	ScanPingTimeout = 20 * time.Millisecond
+
+	// pingStatusLength is the size of a Protocol 2.0 ping status packet
+	pingStatusLength = 14
+	// defaultScanBaud is assumed when Driver.BaudRate is not set
+	defaultScanBaud = 57600
+)
+
+// broadcastPingWindow returns how long to collect broadcast ping answers.
+// Devices reply one after another in ID order, each after its Return Delay
+// Time, so the window must cover every possible ID.
+func (d *Driver) broadcastPingWindow() time.Duration {
+	baud := d.BaudRate
+	if baud <= 0 {
+		baud = defaultScanBaud
+	}
+	// 10 bits per byte on the wire (start + 8 data + stop)
+	packetTime := time.Duration(pingStatusLength*10) * time.Second / time.Duration(baud)
+	return time.Duration(MaxID+1)*(d.ReturnDelay+packetTime) + 3*time.Millisecond
+}
+
+// Scan discovers every motor on the bus.
+// With Protocol 2.0 a single broadcast ping is sent and all status packets
+// arriving within the response window are collected. Protocol 1.0 has no
+// broadcast ping answers, so it falls back to ScanRange over all IDs.
+// Results are sorted by ID.
+func (d *Driver) Scan(ctx context.Context) ([]DeviceInfo, error) {
+	if d.proto.Version() == 1 {
+		return d.ScanRange(ctx, 0, MaxID)
+	}
+
+	tx := d.proto.BuildPacket(BroadcastID, InstPing, nil)
+	if err := d.sendPacket(tx); err != nil {
+		return nil, fmt.Errorf("broadcast ping failed: %v", err)
+	}
+
+	deadline := time.Now().Add(d.broadcastPingWindow())
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+
+	found := make(map[uint8]DeviceInfo)
+	for {
+		if err := ctx.Err(); err != nil {
+			return sortDevices(found), err
+		}
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			break
+		}
+
+		rx, err := d.readPacketWithTimeout(remaining)
+		if errors.Is(err, ErrTimeout) {
+			break
+		}
+		if err != nil {
+			return sortDevices(found), fmt.Errorf("scan read failed: %v", err)
+		}
+
+		// Skip malformed packets; a corrupted answer should not abort the scan
+		id, errCode, params, err := d.proto.ParsePacket(rx)
+		if err != nil {
+			continue
+		}
+		if err := d.proto.statusError(id, errCode); err != nil && !isAlertOnly(err) {
+			continue
+		}
+		info, err := parsePingParams(id, params)
+		if err != nil {
+			continue
+		}
+		found[id] = info
+	}
+
+	return sortDevices(found), nil
+}
+
+// ScanRange pings each ID from first to last (inclusive) in turn.
+// Slower than a broadcast scan but works with Protocol 1.0 and with devices
+// that do not answer broadcast pings. Results are sorted by ID.
+func (d *Driver) ScanRange(ctx context.Context, first, last uint8) ([]DeviceInfo, error) {
+	if first > last || last > MaxID {
+		return nil, fmt.Errorf("invalid scan range %d-%d", first, last)
+	}
+
+	saved := d.Timeout
+	if d.Timeout > ScanPingTimeout {
+		d.Timeout = ScanPingTimeout
+	}
+	defer func() { d.Timeout = saved }()
+
+	found := make(map[uint8]DeviceInfo)
+	for id := int(first); id <= int(last); id++ {
+		if err := ctx.Err(); err != nil {
+			return sortDevices(found), err
+		}
+		info, err := d.PingDevice(uint8(id))
+		if err != nil {
+			continue
+		}
+		found[uint8(id)] = info
+	}
+
+	return sortDevices(found), nil
+}
+
+// sortDevices returns the found devices ordered by ID
+func sortDevices(found map[uint8]DeviceInfo) []DeviceInfo {
+	devices := make([]DeviceInfo, 0, len(found))
+	for _, info := range found {
+		devices = append(devices, info)
+	}
+	sort.Slice(devices, func(i, j int) bool { return devices[i].ID < devices[j].ID })
+	return devices
+}
@@ -0,0 +1,85 @@
+package dxl
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDriverScanBroadcast(t *testing.T) {
+	// Devices answer the broadcast ping back to back
+	response := buildStatusPacket(1, 0, []byte{0x26, 0x04, 0x2D}) // XM430-W350 (1062), fw 45
+	response = append(response, buildStatusPacket(3, 0, []byte{0xA4, 0x04, 0x2E})...)
+	mock := NewScriptedSerialPort(response)
+
+	driver := NewDriver(mock)
+	driver.BaudRate = 4000000
+	driver.ReturnDelay = 0
+
+	devices, err := driver.Scan(context.Background())
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if len(devices) != 2 {
+		t.Fatalf("Expected 2 devices, got %d: %+v", len(devices), devices)
+	}
+	if devices[0] != (DeviceInfo{ID: 1, ModelNumber: 1062, Firmware: 45}) {
+		t.Errorf("Device 0 mismatch: %+v", devices[0])
+	}
+	if devices[1].ID != 3 || devices[1].ModelNumber != 1188 {
+		t.Errorf("Device 1 mismatch: %+v", devices[1])
+	}
+
+	written := mock.GetWritten()
+	if written[4] != BroadcastID || written[7] != InstPing {
+		t.Errorf("Expected broadcast ping, got %X", written)
+	}
+}
+
+func TestDriverScanContextCancel(t *testing.T) {
+	driver := NewDriver(NewScriptedSerialPort())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	devices, _ := driver.Scan(ctx)
+	if len(devices) != 0 {
+		t.Errorf("Expected no devices, got %+v", devices)
+	}
+	if elapsed := time.Since(start); elapsed > 200*time.Millisecond {
+		t.Errorf("Scan ignored context deadline: took %v", elapsed)
+	}
+}
+
+func TestDriverScanRange(t *testing.T) {
+	mock := NewScriptedSerialPort(
+		nil, // ID 1 absent
+		buildStatusPacket(2, 0, []byte{0x26, 0x04, 0x2D}),
+		nil, // ID 3 absent
+	)
+	driver := NewDriver(mock)
+
+	devices, err := driver.ScanRange(context.Background(), 1, 3)
+	if err != nil {
+		t.Fatalf("ScanRange failed: %v", err)
+	}
+	if len(devices) != 1 || devices[0].ID != 2 {
+		t.Errorf("Expected only ID 2, got %+v", devices)
+	}
+	if driver.Timeout != DefaultTimeout {
+		t.Errorf("Driver timeout not restored: %v", driver.Timeout)
+	}
+}
+
+func TestBroadcastPingWindow(t *testing.T) {
+	driver := NewDriver(NewMockSerialPort())
+	slow := driver.broadcastPingWindow()
+
+	driver.BaudRate = 1000000
+	fast := driver.broadcastPingWindow()
+
+	if fast >= slow {
+		t.Errorf("Window should shrink with baud rate: 57600=%v, 1M=%v", slow, fast)
+	}
+}
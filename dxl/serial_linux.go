@@ -13,6 +13,7 @@ import (
 const (
 	TCGETS = 0x5401
 	TCSETS = 0x5402
+	TCFLSH = 0x540B
 )
 
 // SerialPort represents a Linux serial file descriptor
@@ -39,6 +40,18 @@ func OpenSerial(portName string, baudRate int) (*SerialPort, error) {
 	return sp, nil
 }
 
+// SetBaudRate reconfigures the open port to a new baud rate and discards any
+// pending input received at the old rate.
+func (sp *SerialPort) SetBaudRate(baudRate int) error {
+	if err := sp.setParams(baudRate); err != nil {
+		return err
+	}
+	if _, _, err := syscall.Syscall(syscall.SYS_IOCTL, uintptr(sp.fd), uintptr(TCFLSH), uintptr(syscall.TCIOFLUSH)); err != 0 {
+		return fmt.Errorf("ioctl TCFLSH failed: %v", err)
+	}
+	return nil
+}
+
 func (sp *SerialPort) Close() error {
 	return syscall.Close(sp.fd)
 }
@@ -79,7 +92,7 @@ func (sp *SerialPort) setParams(baudRate int) error {
 
 	cbaud := getBaudRateConst(baudRate)
 	if cbaud == 0 {
-		cbaud = syscall.B115200
+		return fmt.Errorf("unsupported baud rate: %d", baudRate)
 	}
 	term.Cflag |= cbaud
 
@@ -130,5 +143,6 @@ func getBaudRateConst(baud int) uint32 {
 	case 4000000:
 		return syscall.B4000000
 	}
-	return syscall.B115200 // Default fallback
+	// Not representable with standard termios (e.g. 4.5M needs termios2)
+	return 0
 }
@@ -124,6 +124,12 @@ func (sp *SerialPort) Write(b []byte) (int, error) {
 	return int(n), err
 }
 
+// SetBaudRate reconfigures the open port to a new baud rate.
+// setParams also purges both buffers, dropping input received at the old rate.
+func (sp *SerialPort) SetBaudRate(baud int) error {
+	return sp.setParams(baud)
+}
+
 // Internal DLL loading
 var (
 	modkernel32         = syscall.NewLazyDLL("kernel32.dll")
@@ -0,0 +1,395 @@
+package dxl
+
+import (
+	"fmt"
+	"time"
+)
+
+// StateField selects a quantity the control loop polls each cycle
+type StateField uint16
+
+const (
+	FieldPosition      StateField = 1 << iota // Present Position
+	FieldVelocity                             // Present Velocity
+	FieldCurrent                              // Present Current, or Present Load on models without current sensing
+	FieldPWM                                  // Present PWM
+	FieldInputVoltage                         // Present Input Voltage
+	FieldTemperature                          // Present Temperature
+	FieldMoving                               // Moving
+	FieldMovingStatus                         // Moving Status
+	FieldHardwareError                        // Hardware Error Status
+	FieldRealtimeTick                         // Realtime Tick
+
+	// FieldsAll polls every field the model provides
+	FieldsAll = FieldPosition | FieldVelocity | FieldCurrent | FieldPWM | FieldInputVoltage |
+		FieldTemperature | FieldMoving | FieldMovingStatus | FieldHardwareError | FieldRealtimeTick
+)
+
+// DefaultStateFields is what the control loop polls unless configured
+const DefaultStateFields = FieldPosition
+
+// stateFieldItems maps each field to the control table item(s) providing it,
+// in order of preference
+var stateFieldItems = []struct {
+	field StateField
+	names []string
+}{
+	{FieldPosition, []string{"Present_Position"}},
+	{FieldVelocity, []string{"Present_Velocity"}},
+	{FieldCurrent, []string{"Present_Current", "Present_Load"}},
+	{FieldPWM, []string{"Present_PWM"}},
+	{FieldInputVoltage, []string{"Present_Input_Voltage"}},
+	{FieldTemperature, []string{"Present_Temperature"}},
+	{FieldMoving, []string{"Moving"}},
+	{FieldMovingStatus, []string{"Moving_Status"}},
+	{FieldHardwareError, []string{"Hardware_Error_Status"}},
+	{FieldRealtimeTick, []string{"Realtime_Tick"}},
+}
+
+// MotorState is one motor's polled state, in raw register units
+// (see Units for conversions)
+type MotorState struct {
+	Fields StateField // Fields actually read this cycle
+
+	Position      int32
+	Velocity      int32
+	Current       int16 // Present Current, or Present Load (0.1 %) on models without current sensing
+	PWM           int16
+	InputVoltage  uint16
+	Temperature   uint8
+	Moving        bool
+	MovingStatus  uint8
+	HardwareError HardwareError
+	RealtimeTick  uint16
+}
+
+// MotorStateSI is the SI counterpart of the numeric MotorState fields. Only
+// fields present in MotorState.Fields are set.
+type MotorStateSI struct {
+	Position    float64 // rad
+	Velocity    float64 // rad/s
+	Current     float64 // A; stays 0 on models reporting Present Load
+	PWM         float64 // Percent of supply voltage
+	Voltage     float64 // V
+	Temperature float64 // °C
+}
+
+// Has reports whether a field was read
+func (s MotorState) Has(f StateField) bool {
+	return s.Fields&f == f
+}
+
+func (s *MotorState) set(f StateField, v int64) {
+	s.Fields |= f
+	switch f {
+	case FieldPosition:
+		s.Position = int32(v)
+	case FieldVelocity:
+		s.Velocity = int32(v)
+	case FieldCurrent:
+		s.Current = int16(v)
+	case FieldPWM:
+		s.PWM = int16(v)
+	case FieldInputVoltage:
+		s.InputVoltage = uint16(v)
+	case FieldTemperature:
+		s.Temperature = uint8(v)
+	case FieldMoving:
+		s.Moving = v != 0
+	case FieldMovingStatus:
+		s.MovingStatus = uint8(v)
+	case FieldHardwareError:
+		s.HardwareError = HardwareError(v)
+	case FieldRealtimeTick:
+		s.RealtimeTick = uint16(v)
+	}
+}
+
+// statePlan describes how to read the polled fields in one transaction:
+// either a span covering all items, or an indirect block
+type statePlan struct {
+	fields   StateField
+	items    []stateItem
+	addr     uint16
+	length   uint16
+	indirect *IndirectMap
+}
+
+type stateItem struct {
+	field  StateField
+	item   ControlItem
+	offset uint16 // Offset in the read buffer
+}
+
+// newStatePlan lays out the requested fields for a model. Fields the model
+// lacks are dropped. With indirectStart > 0 the items are packed into an
+// indirect block starting at that slot; otherwise one read spans them all.
+func newStatePlan(def *ModelDefinition, fields StateField, indirectStart int) (*statePlan, error) {
+	plan := &statePlan{}
+	var names []string
+	for _, fi := range stateFieldItems {
+		if fields&fi.field == 0 {
+			continue
+		}
+		for _, name := range fi.names {
+			if it, ok := def.Item(name); ok {
+				plan.fields |= fi.field
+				plan.items = append(plan.items, stateItem{field: fi.field, item: it})
+				names = append(names, name)
+				break
+			}
+		}
+	}
+	if len(plan.items) == 0 {
+		return nil, fmt.Errorf("%s provides none of the requested state fields", def.Name)
+	}
+
+	if indirectStart > 0 {
+		m, err := NewIndirectMap(def, indirectStart, names...)
+		if err != nil {
+			return nil, err
+		}
+		plan.indirect = m
+		plan.addr, plan.length = m.DataBase, m.Length
+		for i := range plan.items {
+			f, _ := m.Field(plan.items[i].item.Name)
+			plan.items[i].offset = f.Offset
+		}
+		return plan, nil
+	}
+
+	lo, hi := plan.items[0].item.Addr, uint16(0)
+	for _, si := range plan.items {
+		if si.item.Addr < lo {
+			lo = si.item.Addr
+		}
+		if end := si.item.Addr + uint16(si.item.Size); end > hi {
+			hi = end
+		}
+	}
+	plan.addr, plan.length = lo, hi-lo
+	for i := range plan.items {
+		plan.items[i].offset = plan.items[i].item.Addr - lo
+	}
+	return plan, nil
+}
+
+// positionPlan reads only Present Position from the configured address, for
+// motors without a registry definition
+func positionPlan(addr uint16) *statePlan {
+	it := ControlItem{Name: "Present_Position", Addr: addr, Size: 4, Signed: true, Access: AccessRead, Memory: RAM}
+	return &statePlan{
+		fields: FieldPosition,
+		items:  []stateItem{{field: FieldPosition, item: it}},
+		addr:   addr,
+		length: 4,
+	}
+}
+
+// sameLayout reports whether two plans read the same bytes the same way, so
+// the motors can share one Sync Read
+func (p *statePlan) sameLayout(o *statePlan) bool {
+	if p.addr != o.addr || p.length != o.length || len(p.items) != len(o.items) {
+		return false
+	}
+	for i := range p.items {
+		a, b := p.items[i], o.items[i]
+		if a.field != b.field || a.offset != b.offset || a.item.Size != b.item.Size || a.item.Signed != b.item.Signed {
+			return false
+		}
+	}
+	return true
+}
+
+// decode extracts the planned fields from a read buffer
+func (p *statePlan) decode(data []byte) (MotorState, error) {
+	var st MotorState
+	if len(data) != int(p.length) {
+		return st, fmt.Errorf("invalid data length %d, expected %d", len(data), p.length)
+	}
+	for _, si := range p.items {
+		v, err := decodeItem(si.item, data[si.offset:si.offset+uint16(si.item.Size)])
+		if err != nil {
+			return st, err
+		}
+		st.set(si.field, v)
+	}
+	return st, nil
+}
+
+// SetStateFields selects the fields the control loop polls (default
+// DefaultStateFields). Fewer fields mean shorter packets and less bus time.
+// Fields the motor model lacks are skipped; MotorState.Fields tells what
+// was read. With indirect feedback enabled this must be called before Start.
+// Thread-safe: can be called while control loop is running
+func (c *Controller) SetStateFields(fields StateField) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.indirectReady {
+		return fmt.Errorf("state fields are fixed by the indirect block once started")
+	}
+	c.stateFields = fields
+	c.statePlans = nil // Rebuilt on next read
+	return nil
+}
+
+// SetIndirectFeedback packs the polled fields into an Indirect Data block
+// starting at Indirect_Address_<start>, so they arrive in one short read even
+// when the registers are far apart. The table is programmed at Start, before
+// torque is enabled. 0 (the default) reads one span covering all fields.
+func (c *Controller) SetIndirectFeedback(start int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.indirectStart = start
+	c.statePlans = nil
+}
+
+// getStatePlans returns the read plan of each motor, building missing ones.
+// Each motor's plan follows its own model, so mixed buses read correctly.
+func (c *Controller) getStatePlans(ids []uint8) (map[uint8]*statePlan, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.statePlans == nil {
+		c.statePlans = make(map[uint8]*statePlan)
+	}
+	for _, id := range ids {
+		if _, ok := c.statePlans[id]; ok {
+			continue
+		}
+		def, ok := c.models[id]
+		switch {
+		case ok:
+			plan, err := newStatePlan(def, c.stateFields, c.indirectStart)
+			if err != nil {
+				return nil, fmt.Errorf("motor %d: %v", id, err)
+			}
+			c.statePlans[id] = plan
+		case c.stateFields&^FieldPosition != 0 || c.indirectStart > 0:
+			return nil, fmt.Errorf("motor %d: polling fields other than position needs a registry model definition", id)
+		default:
+			c.statePlans[id] = positionPlan(c.modelForLocked(id).AddrPresentPosition)
+		}
+	}
+	return c.statePlans, nil
+}
+
+// prepareStatePlan builds the read plans at Start and programs the indirect
+// table into each motor if one is used. Torque must still be off.
+func (c *Controller) prepareStatePlan(ids []uint8) error {
+	plans, err := c.getStatePlans(ids)
+	if err != nil {
+		return err
+	}
+	c.busMu.Lock()
+	defer c.busMu.Unlock()
+	for _, id := range ids {
+		plan := plans[id]
+		if plan.indirect == nil {
+			continue
+		}
+		if err := c.driver.ProgramIndirect(id, plan.indirect); err != nil {
+			return fmt.Errorf("failed to program indirect feedback for ID %d: %v", id, err)
+		}
+	}
+	c.mu.Lock()
+	c.indirectReady = c.indirectStart > 0
+	c.mu.Unlock()
+	return nil
+}
+
+// readFeedback polls the planned fields from all motors: one Sync Read when
+// every motor's fields sit at the same addresses, a Bulk Read otherwise.
+// Each Feedback gets the same timestamp and sequence number. A motor with an
+// alert-only status error still reports its state, with the alert in Error.
+func (c *Controller) readFeedback(motorIDs []uint8) []Feedback {
+	c.seq++
+	feedbacks := make([]Feedback, len(motorIDs))
+	for i, id := range motorIDs {
+		feedbacks[i] = Feedback{ID: id, Seq: c.seq}
+	}
+
+	if len(motorIDs) == 0 {
+		return feedbacks
+	}
+
+	plans, err := c.getStatePlans(motorIDs)
+	if err != nil {
+		now := time.Now()
+		for i := range feedbacks {
+			feedbacks[i].Error, feedbacks[i].Timestamp = err, now
+		}
+		return feedbacks
+	}
+
+	uniform := true
+	for _, id := range motorIDs {
+		if !plans[id].sameLayout(plans[motorIDs[0]]) {
+			uniform = false
+			break
+		}
+	}
+
+	c.busMu.Lock()
+	var results []SyncReadData
+	switch {
+	case !c.isSyncMode():
+		// Individual reads for single motor
+		results = make([]SyncReadData, len(motorIDs))
+		for i, id := range motorIDs {
+			p := plans[id]
+			data, rerr := c.driver.Read(id, p.addr, p.length)
+			results[i] = SyncReadData{ID: id, Data: data, Err: rerr}
+		}
+	case uniform:
+		p := plans[motorIDs[0]]
+		if c.isFastSyncRead() {
+			results, err = c.driver.FastSyncRead(p.addr, p.length, motorIDs)
+		} else {
+			results, err = c.driver.SyncRead(p.addr, p.length, motorIDs)
+		}
+	default:
+		// Layouts differ (mixed models): per-motor address and length
+		requests := make([]BulkReadRequest, len(motorIDs))
+		for i, id := range motorIDs {
+			requests[i] = BulkReadRequest{ID: id, Addr: plans[id].addr, Length: plans[id].length}
+		}
+		if c.isFastSyncRead() && c.driver.Protocol().supports(InstFastBulkRead) {
+			results, err = c.driver.FastBulkRead(requests)
+		} else {
+			results, err = c.driver.BulkRead(requests)
+		}
+	}
+	c.busMu.Unlock()
+	now := time.Now()
+
+	byID := make(map[uint8]SyncReadData, len(results))
+	for _, r := range results {
+		byID[r.ID] = r
+	}
+	for i := range feedbacks {
+		fb := &feedbacks[i]
+		fb.Timestamp = now
+		if err != nil {
+			fb.Error = err
+			continue
+		}
+		r, ok := byID[fb.ID]
+		if !ok {
+			fb.Error = fmt.Errorf("no data for motor %d", fb.ID)
+			continue
+		}
+		if r.Err != nil && !isAlertOnly(r.Err) {
+			fb.Error = r.Err
+			continue
+		}
+		st, derr := plans[fb.ID].decode(r.Data)
+		if derr != nil {
+			fb.Error = fmt.Errorf("motor %d: %v", fb.ID, derr)
+			continue
+		}
+		fb.State = st
+		fb.Value = uint32(st.Position)
+		fb.Error = r.Err
+	}
+	return feedbacks
+}
@@ -0,0 +1,135 @@
+package dxl
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestStatePlanSpan(t *testing.T) {
+	xm, _ := LookupModel(1020)
+
+	// Position only: same 4-byte read as before
+	plan, err := newStatePlan(xm, FieldPosition, 0)
+	if err != nil || plan.addr != 132 || plan.length != 4 {
+		t.Fatalf("position plan: %+v, %v", plan, err)
+	}
+
+	// Velocity..Temperature are contiguous: 128..146
+	plan, err = newStatePlan(xm, FieldPosition|FieldVelocity|FieldTemperature, 0)
+	if err != nil || plan.addr != 128 || plan.length != 19 {
+		t.Fatalf("span plan: addr %d length %d, %v", plan.addr, plan.length, err)
+	}
+}
+
+func TestStatePlanFallbacks(t *testing.T) {
+	// XL430 reports load in place of current
+	xl, _ := LookupModel(1060)
+	plan, err := newStatePlan(xl, FieldCurrent, 0)
+	if err != nil || plan.items[0].item.Name != "Present_Load" {
+		t.Fatalf("XL430 current plan: %+v, %v", plan, err)
+	}
+
+	// Legacy PRO has no PWM: dropped, not an error
+	h54, _ := LookupModel(54024)
+	plan, err = newStatePlan(h54, FieldPosition|FieldPWM, 0)
+	if err != nil || plan.fields != FieldPosition {
+		t.Errorf("H54 plan fields: %v, %v", plan.fields, err)
+	}
+	if _, err := newStatePlan(h54, FieldPWM, 0); err == nil {
+		t.Error("Expected error when no requested field exists")
+	}
+}
+
+func TestControllerReadFeedbackState(t *testing.T) {
+	block := func(pos int32, temp byte) []byte {
+		b := make([]byte, 19) // 128..146
+		b[0] = 0xF6           // Velocity -10
+		b[1], b[2], b[3] = 0xFF, 0xFF, 0xFF
+		b[4] = byte(pos) // Position at 132
+		b[5] = byte(pos >> 8)
+		b[18] = temp // Temperature at 146
+		return b
+	}
+	mock := NewScriptedSerialPort(append(
+		buildStatusPacket(1, 0, block(2048, 40)),
+		buildStatusPacket(2, alertBit, block(1024, 75))...,
+	))
+	ctrl := newTestController(mock, []uint8{1, 2})
+	if err := ctrl.configureModels([]uint8{1, 2}, map[uint8]uint16{1: 1020, 2: 1020}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctrl.SetStateFields(FieldPosition | FieldVelocity | FieldTemperature); err != nil {
+		t.Fatal(err)
+	}
+
+	fbs := ctrl.readFeedback([]uint8{1, 2})
+	if len(fbs) != 2 {
+		t.Fatalf("Expected 2 feedbacks, got %d", len(fbs))
+	}
+	if fbs[0].Error != nil || fbs[0].State.Position != 2048 || fbs[0].State.Velocity != -10 || fbs[0].State.Temperature != 40 {
+		t.Errorf("motor 1: %+v", fbs[0])
+	}
+	if fbs[0].Value != 2048 {
+		t.Errorf("Value should mirror position, got %d", fbs[0].Value)
+	}
+	if fbs[0].State.Has(FieldCurrent) {
+		t.Error("Current was not polled")
+	}
+
+	// Alert: state still valid, alert reported
+	var se *StatusError
+	if !errors.As(fbs[1].Error, &se) || !se.Alert {
+		t.Errorf("motor 2 should carry the alert, got %v", fbs[1].Error)
+	}
+	if fbs[1].State.Temperature != 75 {
+		t.Errorf("motor 2 temperature: got %d", fbs[1].State.Temperature)
+	}
+
+	if fbs[0].Seq != 1 || fbs[1].Seq != 1 || fbs[0].Timestamp.IsZero() {
+		t.Errorf("seq/timestamp: %d %d %v", fbs[0].Seq, fbs[1].Seq, fbs[0].Timestamp)
+	}
+	if next := ctrl.readFeedback([]uint8{1, 2}); next[0].Seq != 2 {
+		t.Errorf("Seq should increase, got %d", next[0].Seq)
+	}
+}
+
+func TestControllerIndirectFeedback(t *testing.T) {
+	mock := NewScriptedSerialPort(
+		buildStatusPacket(1, 0, nil), // Indirect table
+		buildStatusPacket(1, 0, []byte{0x00, 0x08, 0x00, 0x00, byte(HwErrOverload)}),
+	)
+	ctrl := newTestController(mock, []uint8{1})
+	if err := ctrl.configureModels([]uint8{1}, map[uint8]uint16{1: 1020}); err != nil {
+		t.Fatal(err)
+	}
+	ctrl.SetIndirectFeedback(1)
+	if err := ctrl.SetStateFields(FieldPosition | FieldHardwareError); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctrl.prepareStatePlan([]uint8{1}); err != nil {
+		t.Fatalf("prepareStatePlan failed: %v", err)
+	}
+	// Indirect Address 1..5 -> 132,133,134,135,70
+	if !bytes.Contains(mock.GetWritten(), []byte{168, 0, 132, 0, 133, 0, 134, 0, 135, 0, 70, 0}) {
+		t.Errorf("indirect table not programmed: %X", mock.GetWritten())
+	}
+
+	fbs := ctrl.readFeedback([]uint8{1})
+	if fbs[0].Error != nil || fbs[0].State.Position != 2048 || fbs[0].State.HardwareError != HwErrOverload {
+		t.Errorf("indirect feedback: %+v", fbs[0])
+	}
+
+	if err := ctrl.SetStateFields(FieldsAll); err == nil {
+		t.Error("Changing fields after programming the indirect block should fail")
+	}
+}
+
+func TestControllerStateFieldsNeedModel(t *testing.T) {
+	ctrl := newTestController(NewScriptedSerialPort(), []uint8{1})
+	ctrl.SetStateFields(FieldPosition | FieldTemperature)
+	fbs := ctrl.readFeedback([]uint8{1})
+	if fbs[0].Error == nil {
+		t.Error("Expected error without a model definition")
+	}
+}
@@ -240,13 +240,11 @@ func (e *TrajectoryExecutor) ExecuteWithContext(ctx context.Context, profile *Tr
 	for i, point := range points {
 		position := clampToUint32(point.Position)
 
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case e.controller.CommandChan <- []Command{
-			{ID: e.motorID, Value: position},
-		}:
+		if err := ctx.Err(); err != nil {
+			return err
 		}
+		// Never blocks: a point the loop has not written yet is replaced
+		e.controller.Send(Command{ID: e.motorID, Value: position, Timestamp: time.Now()})
 
 		// Wait for next update (except for last point)
 		if i < len(points)-1 {
@@ -0,0 +1,210 @@
+package dxl
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Units converts raw control table values of one motor to SI units.
+// A zero scale means the model does not report that quantity; conversions
+// through it return 0.
+type Units struct {
+	TicksPerRad  float64 // Position resolution
+	ZeroTick     int64   // Present Position value at 0 rad
+	HomingOffset int64   // Homing Offset register, already included in Present Position
+
+	RPMPerUnit     float64 // Velocity
+	AmpsPerUnit    float64 // Current
+	PercentPerUnit float64 // PWM
+	VoltsPerUnit   float64 // Input voltage
+	CelsiusPerUnit float64 // Temperature
+}
+
+// XSeriesUnits are the scales of X-series motors with current sensing
+// (XM430/XM540/XH/XW: 2.69 mA per unit)
+var XSeriesUnits = Units{
+	TicksPerRad:    4095 / (2 * math.Pi),
+	ZeroTick:       2048,
+	RPMPerUnit:     0.229,
+	AmpsPerUnit:    0.00269,
+	PercentPerUnit: 100.0 / 885,
+	VoltsPerUnit:   0.1,
+	CelsiusPerUnit: 1,
+}
+
+const radPerSecPerRPM = 2 * math.Pi / 60
+
+// PositionToRad converts a Present/Goal Position to radians in the homed
+// frame (Homing Offset applied, as reported by the motor)
+func (u Units) PositionToRad(raw int64) float64 {
+	if u.TicksPerRad == 0 {
+		return 0
+	}
+	return float64(raw-u.ZeroTick) / u.TicksPerRad
+}
+
+// RadToPosition converts radians in the homed frame to a Goal Position
+func (u Units) RadToPosition(rad float64) int64 {
+	return int64(math.Round(rad*u.TicksPerRad)) + u.ZeroTick
+}
+
+// PositionToDeg converts a position to degrees in the homed frame
+func (u Units) PositionToDeg(raw int64) float64 {
+	return u.PositionToRad(raw) * 180 / math.Pi
+}
+
+// DegToPosition converts degrees in the homed frame to a Goal Position
+func (u Units) DegToPosition(deg float64) int64 {
+	return u.RadToPosition(deg * math.Pi / 180)
+}
+
+// MotorAngle converts a Present Position to the shaft angle before the
+// Homing Offset was applied
+func (u Units) MotorAngle(raw int64) float64 {
+	return u.PositionToRad(raw - u.HomingOffset)
+}
+
+// HomingOffsetFor returns the Homing Offset that makes a motor currently at
+// present read rad instead
+func (u Units) HomingOffsetFor(present int64, rad float64) int64 {
+	return u.HomingOffset + u.RadToPosition(rad) - present
+}
+
+// VelocityToRPM converts a Present/Goal Velocity to rev/min
+func (u Units) VelocityToRPM(raw int64) float64 {
+	return float64(raw) * u.RPMPerUnit
+}
+
+// RPMToVelocity converts rev/min to a Goal Velocity
+func (u Units) RPMToVelocity(rpm float64) int64 {
+	return scaleToRaw(rpm, u.RPMPerUnit)
+}
+
+// VelocityToRadPerSec converts a Present/Goal Velocity to rad/s
+func (u Units) VelocityToRadPerSec(raw int64) float64 {
+	return u.VelocityToRPM(raw) * radPerSecPerRPM
+}
+
+// RadPerSecToVelocity converts rad/s to a Goal Velocity
+func (u Units) RadPerSecToVelocity(radps float64) int64 {
+	return u.RPMToVelocity(radps / radPerSecPerRPM)
+}
+
+// CurrentToAmps converts a Present/Goal Current to amperes
+func (u Units) CurrentToAmps(raw int64) float64 {
+	return float64(raw) * u.AmpsPerUnit
+}
+
+// AmpsToCurrent converts amperes to a Goal Current
+func (u Units) AmpsToCurrent(amps float64) int64 {
+	return scaleToRaw(amps, u.AmpsPerUnit)
+}
+
+// PWMToPercent converts a Present/Goal PWM to percent of supply voltage
+func (u Units) PWMToPercent(raw int64) float64 {
+	return float64(raw) * u.PercentPerUnit
+}
+
+// PercentToPWM converts percent of supply voltage to a Goal PWM
+func (u Units) PercentToPWM(percent float64) int64 {
+	return scaleToRaw(percent, u.PercentPerUnit)
+}
+
+// VoltageToVolts converts Present Input Voltage to volts
+func (u Units) VoltageToVolts(raw int64) float64 {
+	return float64(raw) * u.VoltsPerUnit
+}
+
+// TemperatureToCelsius converts Present Temperature to °C
+func (u Units) TemperatureToCelsius(raw int64) float64 {
+	return float64(raw) * u.CelsiusPerUnit
+}
+
+func scaleToRaw(v, perUnit float64) int64 {
+	if perUnit == 0 {
+		return 0
+	}
+	return int64(math.Round(v / perUnit))
+}
+
+// Units derives unit scales from the control table: position resolution from
+// the ROBOTIS [type info] keys, the rest from the Unit of the Present_* items.
+func (m *ModelDefinition) Units() Units {
+	var u Units
+	info := m.TypeInfo
+	maxPos, okMax := info["value_of_max_radian_position"]
+	minPos, okMin := info["value_of_min_radian_position"]
+	maxRad, okMaxRad := info["max_radian"]
+	minRad, okMinRad := info["min_radian"]
+	if okMax && okMin && okMaxRad && okMinRad && maxRad > minRad {
+		u.TicksPerRad = (maxPos - minPos) / (maxRad - minRad)
+	} else {
+		// .model unit info gives rad per tick
+		for _, name := range []string{"Present_Position", "Goal_Position"} {
+			if scale, unit := m.itemUnit(name); unit == "rad" && scale > 0 {
+				u.TicksPerRad = 1 / scale
+				break
+			}
+		}
+	}
+	u.ZeroTick = int64(info["value_of_zero_radian_position"])
+
+	if scale, unit := m.itemUnit("Present_Velocity"); scale != 0 {
+		switch unit {
+		case "rev/min", "rpm":
+			u.RPMPerUnit = scale
+		case "rad/s":
+			u.RPMPerUnit = scale / radPerSecPerRPM
+		}
+	}
+	if scale, unit := m.itemUnit("Present_Current"); scale != 0 {
+		switch unit {
+		case "mA":
+			u.AmpsPerUnit = scale / 1000
+		case "A":
+			u.AmpsPerUnit = scale
+		}
+	}
+	if scale, unit := m.itemUnit("Present_PWM"); unit == "%" {
+		u.PercentPerUnit = scale
+	} else if it, ok := m.Item("PWM_Limit"); ok && it.Max > 0 {
+		// Full PWM range is 100 %
+		u.PercentPerUnit = 100 / float64(it.Max)
+	}
+	if scale, unit := m.itemUnit("Present_Input_Voltage"); unit == "V" {
+		u.VoltsPerUnit = scale
+	}
+	if scale, unit := m.itemUnit("Present_Temperature"); unit == "°C" {
+		u.CelsiusPerUnit = scale
+	}
+	return u
+}
+
+func (m *ModelDefinition) itemUnit(name string) (float64, string) {
+	it, ok := m.Item(name)
+	if !ok {
+		return 0, ""
+	}
+	return parseUnit(it.Unit)
+}
+
+// parseUnit splits an item unit such as "0.229 rev/min" or "°C" into scale
+// and unit name. A bare unit name has scale 1.
+func parseUnit(s string) (float64, string) {
+	fields := strings.Fields(s)
+	switch len(fields) {
+	case 1:
+		if v, err := strconv.ParseFloat(fields[0], 64); err == nil {
+			return v, ""
+		}
+		return 1, fields[0]
+	case 2:
+		v, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			return 0, ""
+		}
+		return v, fields[1]
+	}
+	return 0, ""
+}
@@ -0,0 +1,113 @@
+package dxl
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func approx(a, b float64) bool {
+	return math.Abs(a-b) < 1e-6*math.Max(1, math.Abs(b))
+}
+
+func TestUnitsPosition(t *testing.T) {
+	u := XSeriesUnits
+	if got := u.PositionToRad(2048); got != 0 {
+		t.Errorf("PositionToRad(2048): got %v, want 0", got)
+	}
+	if got := u.PositionToDeg(2048 + 1024); math.Abs(got-90) > 0.1 {
+		t.Errorf("PositionToDeg(3072): got %v, want ~90", got)
+	}
+	for _, raw := range []int64{0, 1000, 2048, 4095, -5000} {
+		if got := u.RadToPosition(u.PositionToRad(raw)); got != raw {
+			t.Errorf("round trip %d: got %d", raw, got)
+		}
+	}
+}
+
+func TestUnitsHomingOffset(t *testing.T) {
+	u := XSeriesUnits
+	u.HomingOffset = 100
+
+	// Present Position includes the offset; the shaft is 100 ticks behind
+	if got := u.MotorAngle(2148); !approx(got, 0) {
+		t.Errorf("MotorAngle(2148): got %v, want 0", got)
+	}
+	// Make the current pose (raw 3000) read 0 rad
+	offset := u.HomingOffsetFor(3000, 0)
+	if offset != 100+2048-3000 {
+		t.Errorf("HomingOffsetFor: got %d", offset)
+	}
+}
+
+func TestUnitsSigned(t *testing.T) {
+	u := XSeriesUnits
+	if got := u.VelocityToRPM(-100); !approx(got, -22.9) {
+		t.Errorf("VelocityToRPM(-100): got %v", got)
+	}
+	if got := u.RadPerSecToVelocity(u.VelocityToRadPerSec(-100)); got != -100 {
+		t.Errorf("velocity round trip: got %d", got)
+	}
+	if got := u.CurrentToAmps(-1000); !approx(got, -2.69) {
+		t.Errorf("CurrentToAmps(-1000): got %v", got)
+	}
+	if got := u.AmpsToCurrent(1.345); got != 500 {
+		t.Errorf("AmpsToCurrent(1.345): got %d", got)
+	}
+	if got := u.PercentToPWM(100); got != 885 {
+		t.Errorf("PercentToPWM(100): got %d", got)
+	}
+	if got := u.VoltageToVolts(120); !approx(got, 12) {
+		t.Errorf("VoltageToVolts(120): got %v", got)
+	}
+
+	// Missing scale: conversions return 0 rather than dividing by zero
+	var none Units
+	if none.AmpsToCurrent(1) != 0 || none.PositionToRad(100) != 0 {
+		t.Error("Zero Units should convert to 0")
+	}
+}
+
+func TestModelDefinitionUnits(t *testing.T) {
+	cases := []struct {
+		number  uint16
+		amps    float64
+		percent float64
+	}{
+		{1020, 0.00269, 0.113},      // XM430-W350
+		{1200, 0.001, 0.113},        // XL330-M288
+		{1060, 0, 0.113},            // XL430: no current sensing
+		{2020, 0.001, 100.0 / 2009}, // H54P: PWM from PWM Limit
+	}
+	for _, c := range cases {
+		def, _ := LookupModel(c.number)
+		u := def.Units()
+		if !approx(u.AmpsPerUnit, c.amps) || !approx(u.PercentPerUnit, c.percent) {
+			t.Errorf("%s: got %+v", def.Name, u)
+		}
+	}
+
+	xm, _ := LookupModel(1020)
+	if u := xm.Units(); !approx(u.TicksPerRad, XSeriesUnits.TicksPerRad) || u.ZeroTick != 2048 || !approx(u.RPMPerUnit, 0.229) {
+		t.Errorf("XM430 units: got %+v", u)
+	}
+	h54, _ := LookupModel(54024)
+	if u := h54.Units(); u.ZeroTick != 0 || math.Abs(u.PositionToRad(250961)-math.Pi) > 1e-3 {
+		t.Errorf("H54 units: got %+v", u)
+	}
+}
+
+func TestModelFileUnits(t *testing.T) {
+	def, err := ParseModelFile(strings.NewReader(testModelFile), 1020, "XM430-W350")
+	if err != nil {
+		t.Fatal(err)
+	}
+	u := def.Units()
+	if u.ZeroTick != 2048 || u.TicksPerRad == 0 {
+		t.Errorf("position from type info: got %+v", u)
+	}
+	// 0.0239691227 rad/s per unit = 0.229 rev/min
+	if math.Abs(u.RPMPerUnit-0.229) > 1e-3 {
+		t.Errorf("RPMPerUnit: got %v", u.RPMPerUnit)
+	}
+}
@@ -0,0 +1,282 @@
+package dxl
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// BusWatchdogUnit is the resolution of the Bus Watchdog register
+	BusWatchdogUnit = 20 * time.Millisecond
+	// MaxBusWatchdog is the longest timeout the register holds (127 units)
+	MaxBusWatchdog = 127 * BusWatchdogUnit
+
+	// busWatchdogTripped is what the register reads after the watchdog fired
+	busWatchdogTripped = -1
+)
+
+// WatchdogCheckInterval is how often the control loop reads the Bus Watchdog
+// registers to catch a trip it did not cause itself. A loop stall longer than
+// the watchdog timeout, or a motor coming back online, triggers a check at once.
+var WatchdogCheckInterval = time.Second
+
+// OfflineProbeInterval is how often an offline motor is pinged. A probe of a
+// silent motor holds the bus for up to Driver.Timeout.
+var OfflineProbeInterval = time.Second
+
+// LinkStatus describes the communication state of one motor
+type LinkStatus struct {
+	Online              bool
+	ConsecutiveTimeouts int
+	LastSeen            time.Time // Last successful read
+}
+
+// SetBusWatchdog enables the Bus Watchdog of every motor that has one, with
+// the given timeout (rounded up to 20 ms, at most MaxBusWatchdog). If the
+// host stops talking to a motor for that long, the motor stops and ignores
+// goals until the watchdog is re-armed, so a crashed process does not leave
+// the arm moving. Each control cycle's feedback read keeps it fed; the loop
+// re-arms a tripped watchdog by itself and reports FaultWatchdog.
+// 0 (the default) leaves the watchdog off. Must be called before Start.
+func (c *Controller) SetBusWatchdog(timeout time.Duration) error {
+	if timeout < 0 || timeout > MaxBusWatchdog {
+		return fmt.Errorf("bus watchdog %v out of range (0-%v)", timeout, MaxBusWatchdog)
+	}
+	if st := c.State(); st != StateIdle {
+		return fmt.Errorf("bus watchdog must be set before Start (controller is %v)", st)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.watchdog = timeout
+	return nil
+}
+
+// getWatchdog returns the configured bus watchdog timeout (thread-safe)
+func (c *Controller) getWatchdog() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.watchdog
+}
+
+// watchdogUnits converts a timeout to register units, rounding up
+func watchdogUnits(timeout time.Duration) int64 {
+	return int64((timeout + BusWatchdogUnit - 1) / BusWatchdogUnit)
+}
+
+// checkWatchdogPeriod makes sure the loop talks to the motors often enough
+// to keep the watchdog fed: at least twice per timeout
+func checkWatchdogPeriod(period, watchdog time.Duration) error {
+	if watchdog > 0 && period > watchdog/2 {
+		return fmt.Errorf("control period %v too long for bus watchdog %v (max %v)", period, watchdog, watchdog/2)
+	}
+	return nil
+}
+
+// configureWatchdog arms the Bus Watchdog at Start. Writing 0 first clears a
+// trip left over from a previous run.
+func (c *Controller) configureWatchdog(ids []uint8) error {
+	watchdog := c.getWatchdog()
+	if watchdog == 0 {
+		return nil
+	}
+	if err := checkWatchdogPeriod(c.getPeriod(), watchdog); err != nil {
+		return err
+	}
+	var armed []uint8
+	for _, id := range ids {
+		def, ok := c.MotorDefinition(id)
+		if !ok {
+			fmt.Printf("Warning: motor %d has no model definition, bus watchdog not set\n", id)
+			continue
+		}
+		if _, ok := def.Item("Bus_Watchdog"); !ok {
+			fmt.Printf("Warning: %s (ID %d) has no Bus Watchdog\n", def.Name, id)
+			continue
+		}
+		if err := c.armWatchdog(id, watchdog); err != nil {
+			return fmt.Errorf("failed to set bus watchdog for ID %d: %v", id, err)
+		}
+		armed = append(armed, id)
+	}
+	c.mu.Lock()
+	c.watchdogIDs = armed
+	c.mu.Unlock()
+	return nil
+}
+
+// armWatchdog clears and sets one motor's Bus Watchdog
+func (c *Controller) armWatchdog(id uint8, watchdog time.Duration) error {
+	if err := c.WriteItem(id, "Bus_Watchdog", 0); err != nil && !isAlertOnly(err) {
+		return err
+	}
+	if err := c.WriteItem(id, "Bus_Watchdog", watchdogUnits(watchdog)); err != nil && !isAlertOnly(err) {
+		return err
+	}
+	return nil
+}
+
+// Link returns the communication state of a motor
+func (c *Controller) Link(id uint8) LinkStatus {
+	c.faultMu.Lock()
+	defer c.faultMu.Unlock()
+	return LinkStatus{
+		Online:              !c.offline[id],
+		ConsecutiveTimeouts: c.commFailures[id],
+		LastSeen:            c.lastSeen[id],
+	}
+}
+
+// OfflineMotors returns the IDs currently marked offline
+func (c *Controller) OfflineMotors() []uint8 {
+	c.faultMu.Lock()
+	defer c.faultMu.Unlock()
+	var ids []uint8
+	for _, id := range c.getMotorIDs() {
+		if c.offline[id] {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// onlineIDs filters out motors marked offline
+func (c *Controller) onlineIDs(ids []uint8) []uint8 {
+	c.faultMu.Lock()
+	defer c.faultMu.Unlock()
+	if len(c.offline) == 0 {
+		return ids
+	}
+	online := make([]uint8, 0, len(ids))
+	for _, id := range ids {
+		if !c.offline[id] {
+			online = append(online, id)
+		}
+	}
+	return online
+}
+
+// withOffline returns feedback for all ids in order, with ErrMotorOffline
+// entries for the motors that were not read
+func (c *Controller) withOffline(ids []uint8, read []Feedback) []Feedback {
+	if len(read) == len(ids) {
+		return read
+	}
+	byID := make(map[uint8]Feedback, len(read))
+	for _, fb := range read {
+		byID[fb.ID] = fb
+	}
+	now := time.Now()
+	all := make([]Feedback, len(ids))
+	for i, id := range ids {
+		fb, ok := byID[id]
+		if !ok {
+			fb = Feedback{ID: id, Seq: c.seq, Timestamp: now, Error: ErrMotorOffline}
+		}
+		all[i] = fb
+	}
+	return all
+}
+
+// serviceLinks probes offline motors and checks the bus watchdogs. gap is the
+// time since the previous feedback read. Control loop only.
+func (c *Controller) serviceLinks(now time.Time, gap time.Duration) {
+	back := c.probeOffline(now)
+
+	watchdog := c.getWatchdog()
+	if watchdog == 0 {
+		return
+	}
+	c.mu.RLock()
+	ids := c.watchdogIDs
+	c.mu.RUnlock()
+
+	// A full check after a stall or periodically; otherwise only motors
+	// that just came back can have tripped unnoticed
+	if gap > watchdog || now.Sub(c.lastWatchdogCheck) >= WatchdogCheckInterval {
+		c.lastWatchdogCheck = now
+	} else {
+		ids = intersectIDs(ids, back)
+	}
+	// A tripped watchdog reads -1; a motor that was power-cycled or
+	// rebooted reads 0. Either way it is re-armed.
+	armed := watchdogUnits(watchdog)
+	for _, id := range c.onlineIDs(ids) {
+		v, err := c.ReadItem(id, "Bus_Watchdog")
+		if err != nil && !isAlertOnly(err) {
+			continue // Timeouts are counted by the feedback read
+		}
+		if v == armed {
+			continue
+		}
+		if err := c.armWatchdog(id, watchdog); err != nil {
+			fmt.Printf("Failed to re-arm bus watchdog for ID %d: %v\n", id, err)
+			continue
+		}
+		if v == busWatchdogTripped {
+			c.react(FaultEvent{ID: id, Class: FaultWatchdog})
+		} else {
+			fmt.Printf("Bus watchdog for ID %d was %d, re-armed\n", id, v)
+		}
+	}
+}
+
+// rearmWatchdog sets the bus watchdog again on a motor that was rebooted,
+// which clears it. Motors configured without one are left alone.
+func (c *Controller) rearmWatchdog(id uint8) error {
+	watchdog := c.getWatchdog()
+	if watchdog == 0 {
+		return nil
+	}
+	c.mu.RLock()
+	ids := c.watchdogIDs
+	c.mu.RUnlock()
+	if len(intersectIDs(ids, []uint8{id})) == 0 {
+		return nil
+	}
+	return c.armWatchdog(id, watchdog)
+}
+
+// probeOffline pings offline motors at most every OfflineProbeInterval and
+// returns those that answered, which are online again
+func (c *Controller) probeOffline(now time.Time) []uint8 {
+	c.faultMu.Lock()
+	var due []uint8
+	for id, off := range c.offline {
+		if off && now.Sub(c.lastProbe[id]) >= OfflineProbeInterval {
+			due = append(due, id)
+			c.lastProbe[id] = now
+		}
+	}
+	c.faultMu.Unlock()
+
+	var back []uint8
+	for _, id := range due {
+		c.busMu.Lock()
+		_, err := c.driver.Ping(id)
+		c.busMu.Unlock()
+		if err != nil && !isAlertOnly(err) {
+			continue
+		}
+		c.faultMu.Lock()
+		delete(c.offline, id)
+		c.commFailures[id] = 0
+		c.lastSeen[id] = time.Now()
+		c.faultMu.Unlock()
+		fmt.Printf("Motor %d back online\n", id)
+		back = append(back, id)
+	}
+	return back
+}
+
+func intersectIDs(a, b []uint8) []uint8 {
+	var out []uint8
+	for _, x := range a {
+		for _, y := range b {
+			if x == y {
+				out = append(out, x)
+				break
+			}
+		}
+	}
+	return out
+}
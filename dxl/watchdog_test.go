@@ -0,0 +1,193 @@
+package dxl
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBusWatchdogSettings(t *testing.T) {
+	if got := watchdogUnits(100 * time.Millisecond); got != 5 {
+		t.Errorf("units(100ms) = %d, want 5", got)
+	}
+	if got := watchdogUnits(50 * time.Millisecond); got != 3 {
+		t.Errorf("units(50ms) = %d, want 3 (rounded up)", got)
+	}
+
+	ctrl := NewController("test", 1000000, ModelXSeries)
+	if err := ctrl.SetBusWatchdog(3 * time.Second); err == nil {
+		t.Error("expected error above MaxBusWatchdog")
+	}
+	if err := ctrl.SetBusWatchdog(100 * time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	// 10 Hz = 100ms period: the watchdog would starve
+	if err := ctrl.SetControlRate(10); err == nil {
+		t.Error("expected error for a period longer than half the watchdog")
+	}
+	if err := ctrl.SetControlRate(100); err != nil {
+		t.Error(err)
+	}
+
+	ctrl.setState(StateRunning, nil)
+	if err := ctrl.SetBusWatchdog(0); err == nil {
+		t.Error("SetBusWatchdog must fail once started")
+	}
+}
+
+func watchdogController(t *testing.T, mock *ScriptedSerialPort) *Controller {
+	t.Helper()
+	ctrl := newTestController(mock, []uint8{1})
+	ctrl.Model = MotorModel{}
+	if err := ctrl.configureModels([]uint8{1}, map[uint8]uint16{1: 1020}); err != nil {
+		t.Fatal(err)
+	}
+	ctrl.setState(StateRunning, nil)
+	return ctrl
+}
+
+func TestConfigureBusWatchdog(t *testing.T) {
+	mock := NewScriptedSerialPort(buildStatusPacket(1, 0, nil), buildStatusPacket(1, 0, nil))
+	ctrl := watchdogController(t, mock)
+	ctrl.watchdog = 100 * time.Millisecond
+
+	if err := ctrl.configureWatchdog([]uint8{1}); err != nil {
+		t.Fatal(err)
+	}
+	written := mock.GetWritten()
+	clear := ctrl.driver.Protocol().BuildPacket(1, InstWrite, []byte{98, 0, 0})
+	arm := ctrl.driver.Protocol().BuildPacket(1, InstWrite, []byte{98, 0, 5})
+	if !bytes.Equal(written, append(clear, arm...)) {
+		t.Errorf("written %X, want clear then 5 units", written)
+	}
+	if len(ctrl.watchdogIDs) != 1 {
+		t.Errorf("watchdogIDs = %v", ctrl.watchdogIDs)
+	}
+}
+
+func TestBusWatchdogRearm(t *testing.T) {
+	mock := NewScriptedSerialPort(
+		buildStatusPacket(1, 0, []byte{0xFF}), // Bus_Watchdog = -1: tripped
+		buildStatusPacket(1, 0, nil),          // Clear
+		buildStatusPacket(1, 0, nil),          // Arm
+	)
+	ctrl := watchdogController(t, mock)
+	ctrl.watchdog = 100 * time.Millisecond
+	ctrl.watchdogIDs = []uint8{1}
+	now := time.Now()
+
+	// Recent check, short gap: nothing read
+	ctrl.lastWatchdogCheck = now
+	ctrl.serviceLinks(now, 10*time.Millisecond)
+	if len(mock.GetWritten()) != 0 {
+		t.Fatalf("unexpected bus traffic %X", mock.GetWritten())
+	}
+
+	// The loop stalled longer than the watchdog: check and re-arm
+	ctrl.serviceLinks(now, 300*time.Millisecond)
+	if !bytes.HasSuffix(mock.GetWritten(), ctrl.driver.Protocol().BuildPacket(1, InstWrite, []byte{98, 0, 5})) {
+		t.Errorf("watchdog not re-armed: %X", mock.GetWritten())
+	}
+	ev := <-ctrl.FaultEvents
+	if ev.ID != 1 || ev.Class != FaultWatchdog || ev.Reaction != ReactionReport {
+		t.Errorf("event = %+v", ev)
+	}
+	if ctrl.State() != StateRunning {
+		t.Errorf("report-only fault changed state to %v", ctrl.State())
+	}
+}
+
+func TestBusWatchdogRearmAfterPowerCycle(t *testing.T) {
+	mock := NewScriptedSerialPort(
+		buildStatusPacket(1, 0, []byte{0}), // Bus_Watchdog = 0: motor came back unarmed
+		buildStatusPacket(1, 0, nil),       // Clear
+		buildStatusPacket(1, 0, nil),       // Arm
+		buildStatusPacket(1, 0, []byte{5}), // Armed
+	)
+	ctrl := watchdogController(t, mock)
+	ctrl.watchdog = 100 * time.Millisecond
+	ctrl.watchdogIDs = []uint8{1}
+	arm := ctrl.driver.Protocol().BuildPacket(1, InstWrite, []byte{98, 0, 5})
+
+	ctrl.serviceLinks(time.Now(), 300*time.Millisecond)
+	if !bytes.HasSuffix(mock.GetWritten(), arm) {
+		t.Errorf("watchdog not re-armed: %X", mock.GetWritten())
+	}
+	select {
+	case ev := <-ctrl.FaultEvents:
+		t.Errorf("an unarmed watchdog is not a trip: %+v", ev)
+	default:
+	}
+
+	// Armed: read only
+	before := len(mock.GetWritten())
+	ctrl.serviceLinks(time.Now(), 300*time.Millisecond)
+	if sent := mock.GetWritten()[before:]; sent[7] != InstRead {
+		t.Errorf("expected only a read, sent %X", sent)
+	}
+}
+
+func TestRecoverMotorRearmsWatchdog(t *testing.T) {
+	mock := NewScriptedSerialPort(
+		buildStatusPacket(1, alertBit, []byte{byte(HwErrOverload)}), // Hardware Error Status
+		buildStatusPacket(1, alertBit, nil),                         // Reboot
+		buildStatusPacket(1, 0, []byte{0xFC, 0x03, 46}),             // Ping
+		buildStatusPacket(1, 0, []byte{0x00, 0x08, 0x00, 0x00}),     // Present Position
+		buildStatusPacket(1, 0, nil),                                // Goal Position
+		buildStatusPacket(1, 0, nil),                                // Torque Enable
+		buildStatusPacket(1, 0, []byte{1}),                          // Torque Enable readback
+		buildStatusPacket(1, 0, nil),                                // Clear
+		buildStatusPacket(1, 0, nil),                                // Arm
+	)
+	ctrl := watchdogController(t, mock)
+	ctrl.watchdog = 100 * time.Millisecond
+	ctrl.watchdogIDs = []uint8{1}
+
+	if _, err := ctrl.RecoverMotor(1); err != nil {
+		t.Fatalf("RecoverMotor failed: %v", err)
+	}
+	if !bytes.HasSuffix(mock.GetWritten(), ctrl.driver.Protocol().BuildPacket(1, InstWrite, []byte{98, 0, 5})) {
+		t.Errorf("watchdog not re-armed after reboot: %X", mock.GetWritten())
+	}
+}
+
+func TestMotorOfflineAndBack(t *testing.T) {
+	saved := OfflineProbeInterval
+	OfflineProbeInterval = 0
+	defer func() { OfflineProbeInterval = saved }()
+
+	mock := NewScriptedSerialPort()
+	ctrl := runningController(mock, []uint8{1})
+	ctrl.driver.Timeout = time.Millisecond
+	ctrl.SetCommLossThreshold(2)
+
+	ctrl.runCycle()
+	if l := ctrl.Link(1); !l.Online || l.ConsecutiveTimeouts != 1 {
+		t.Fatalf("after one timeout: %+v", l)
+	}
+	// The probe right after going offline finds nothing either
+	ctrl.runCycle()
+	if ctrl.Link(1).Online || len(ctrl.OfflineMotors()) != 1 {
+		t.Fatal("motor should be offline after 2 timeouts")
+	}
+	if ev := <-ctrl.FaultEvents; ev.Class != FaultCommLoss || !errors.Is(ev.Err, ErrTimeout) {
+		t.Errorf("event = %+v", ev)
+	}
+
+	// Offline: not read, probed with a ping that now gets an answer
+	mock.mu.Lock()
+	mock.responses = append(mock.responses, buildStatusPacket(1, 0, []byte{0xFC, 0x03, 46}))
+	mock.mu.Unlock()
+	before := len(mock.GetWritten())
+	ctrl.runCycle()
+	if fb, _ := ctrl.LatestFeedback(1); !errors.Is(fb.Error, ErrMotorOffline) {
+		t.Errorf("offline feedback error = %v", fb.Error)
+	}
+	if sent := mock.GetWritten()[before:]; sent[7] != InstPing {
+		t.Errorf("expected only a ping, sent %X", sent)
+	}
+	if l := ctrl.Link(1); !l.Online || l.ConsecutiveTimeouts != 0 {
+		t.Errorf("after probe: %+v", l)
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"go_dxl/dxl"
@@ -13,11 +14,31 @@ func main() {
 	testType := flag.String("test", "", "Test type: position, velocity, torque")
 	portVal := flag.String("port", "COM3", "Serial port name")
 	baudVal := flag.Int("baud", 1000000, "Baudrate")
+	detectVal := flag.Bool("detect", false, "Search all baud rates and protocols for motors")
 
 	flag.Parse()
 
+	if *detectVal {
+		fmt.Printf("Detecting motors on %s (all baud rates, protocol 1.0/2.0)...\n", *portVal)
+		devices, err := dxl.AutoDetect(context.Background(), *portVal, dxl.DetectOptions{})
+		if err != nil {
+			fmt.Printf("Detection failed: %v\n", err)
+			return
+		}
+		if len(devices) == 0 {
+			fmt.Println("No motors found.")
+			return
+		}
+		for _, dev := range devices {
+			fmt.Printf("ID %d Model: %d Firmware: %d @ %d bps, Protocol %d.0\n",
+				dev.ID, dev.ModelNumber, dev.Firmware, dev.BaudRate, dev.Protocol)
+		}
+		return
+	}
+
 	if *testType == "" {
 		fmt.Println("Usage: go run main.go -test [position|velocity|torque] -port [COM3] -baud [1000000]")
+		fmt.Println("       go run main.go -detect -port [COM3]")
 		fmt.Println("Or run individual tests in test/ directory.")
 
 		// Simple Ping Test
@@ -30,11 +51,18 @@ func main() {
 		defer sp.Close()
 
 		driver := dxl.NewDriver(sp)
-		model, err := driver.Ping(1)
+		driver.BaudRate = *baudVal
+		devices, err := driver.Scan(context.Background())
 		if err != nil {
-			fmt.Printf("Ping ID 1 failed: %v\n", err)
-		} else {
-			fmt.Printf("Success! Motor ID 1 Model: %d\n", model)
+			fmt.Printf("Scan failed: %v\n", err)
+			return
+		}
+		if len(devices) == 0 {
+			fmt.Println("No motors found. Check Power/Baudrate.")
+			return
+		}
+		for _, dev := range devices {
+			fmt.Printf("Found Motor ID %d Model: %d Firmware: %d\n", dev.ID, dev.ModelNumber, dev.Firmware)
 		}
 		return
 	}